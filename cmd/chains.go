@@ -111,8 +111,13 @@ $ %s ch s ibc-0 --yaml`, appName, appName, appName, appName)),
 rpc-addr:        %s
 trusting-period: %s
 key:             %s
+keys:            %s
 account-prefix:  %s
-`, c.ChainID, c.RPCAddr, c.TrustingPeriod, c.Key, c.AccountPrefix)
+`, c.ChainID, c.RPCAddr, c.TrustingPeriod, c.Key, strings.Join(c.KeyPool(), ","), c.AccountPrefix)
+				fmt.Println("rpc-endpoints:")
+				for _, st := range c.RPCStatus() {
+					fmt.Printf("  %s\n", rpcStatusLine(st))
+				}
 				return nil
 			}
 		},
@@ -120,6 +125,23 @@ account-prefix:  %s
 	return yamlFlag(jsonFlag(cmd))
 }
 
+// rpcStatusLine describes the health of an RPC endpoint in a line
+func rpcStatusLine(st relayer.RPCEndpointStatus) string {
+	var out string
+	switch {
+	case st.Error != "":
+		out = fmt.Sprintf("%s down: %s", st.Addr, st.Error)
+	case st.CatchingUp:
+		out = fmt.Sprintf("%s catching up, height %d", st.Addr, st.Height)
+	default:
+		out = fmt.Sprintf("%s height %d", st.Addr, st.Height)
+	}
+	if st.Active {
+		out += " (active)"
+	}
+	return out
+}
+
 func chainsEditCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "edit [chain-id] [key] [value]",
@@ -128,7 +150,13 @@ func chainsEditCmd() *cobra.Command {
 		Args:    cobra.ExactArgs(3),
 		Example: strings.TrimSpace(fmt.Sprintf(`
 $ %s chains edit ibc-0 trusting-period 32h
-$ %s ch e ibc-0 trusting-period 32h`, appName, appName)),
+$ %s chains edit ibc-0 confirm-timeout 1m
+$ %s chains edit ibc-0 keys relayer2,relayer3
+$ %s chains edit ibc-0 signer https://signer.example.com:5183
+$ %s chains edit ibc-0 signer-token-file /etc/relayer/signer-token
+$ %s chains edit ibc-0 verify-queries true
+$ %s chains edit ibc-0 rpc-addrs http://10.0.0.2:26657,http://10.0.0.3:26657
+$ %s ch e ibc-0 trusting-period 32h`, appName, appName, appName, appName, appName, appName, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			chain, err := config.Chains.Get(args[0])
 			if err != nil {
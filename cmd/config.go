@@ -328,6 +328,30 @@ func (c *Config) ChainsFromPath(path string) (map[string]*relayer.Chain, string,
 	return chains, src, dst, nil
 }
 
+// PathChains returns copies of the chains of the given path set to its path ends. Unlike the
+// chains returned by ChainsFromPath they can be used alongside the chains of other paths.
+func (c *Config) PathChains(path string) (src, dst *relayer.Chain, err error) {
+	pth, err := c.Paths.Get(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chains, err := c.Chains.Gets(pth.Src.ChainID, pth.Dst.ChainID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	src, dst = chains[pth.Src.ChainID].Copy(), chains[pth.Dst.ChainID].Copy()
+	if err = src.SetPath(pth.Src); err != nil {
+		return nil, nil, err
+	}
+	if err = dst.SetPath(pth.Dst); err != nil {
+		return nil, nil, err
+	}
+
+	return src, dst, nil
+}
+
 // MustYAML returns the yaml string representation of the Paths
 func (c Config) MustYAML() []byte {
 	out, err := yaml.Marshal(c)
@@ -464,10 +488,17 @@ func validateConfig(c *Config) error {
 		return fmt.Errorf("did you remember to run 'rly config init' error:%w", err)
 	}
 
+	// headers and validator sets are cached for all chains
+	relayer.SetLightCacheSize(c.Global.LightCacheSize)
+
 	for _, i := range c.Chains {
 		if err := i.Init(homePath, to, nil, debug); err != nil {
 			return fmt.Errorf("did you remember to run 'rly config init' error:%w", err)
 		}
+		i.SetDryRun(dryRun)
+		if generateOnly {
+			i.SetGenerateOnly(outputDir)
+		}
 	}
 
 	return nil
@@ -554,6 +585,9 @@ func (c *Config) ValidatePath(p *relayer.Path) (err error) {
 	if _, err = p.GetStrategy(); err != nil {
 		return err
 	}
+	if err = p.Filter.Validate(); err != nil {
+		return err
+	}
 	if p.Src.Order != p.Dst.Order {
 		return fmt.Errorf("both sides must have same order ('ORDERED' or 'UNORDERED'), got src(%s) and dst(%s)",
 			p.Src.Order, p.Dst.Order)
@@ -96,7 +96,7 @@ $ %s development listen ibc-2 --no-tx`, appName, appName, appName)),
 
 			done := c.ListenRPCEmitJSON(tx, block, data)
 
-			trapSignal(done)
+			trapSignal(cmd.Context(), done)
 
 			return nil
 		},
@@ -34,6 +34,22 @@ var (
 	flagUpdateAfterExpiry       = "update-after-expiry"
 	flagUpdateAfterMisbehaviour = "update-after-misbehaviour"
 	flagOverride                = "override"
+	flagSweepInterval           = "sweep-interval"
+	flagHealthAddr              = "health-addr"
+	flagShutdownTimeout         = "shutdown-timeout"
+	flagPaths                   = "paths"
+	flagAll                     = "all"
+	flagGranter                 = "granter"
+	flagSpendLimit              = "spend-limit"
+	flagExpiration              = "expiration"
+	flagDryRun                  = "dry-run"
+	flagGenerateOnly            = "generate-only"
+	flagOutputDir               = "output-dir"
+	flagHash                    = "hash"
+	flagForce                   = "force"
+	flagTokenFile               = "token-file"
+	flagTLSCert                 = "tls-cert"
+	flagTLSKey                  = "tls-key"
 )
 
 func ibcDenomFlags(cmd *cobra.Command) *cobra.Command {
@@ -126,6 +142,26 @@ func listenFlag(cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func signerDaemonFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringP(flagListenAddr, "l", "localhost:5183", "sets the signer daemon listener address")
+	cmd.Flags().String(flagTokenFile, "", "file holding the token relayers authenticate with (required)")
+	cmd.Flags().String(flagTLSCert, "", "TLS certificate file, required to listen on other than a loopback address")
+	cmd.Flags().String(flagTLSKey, "", "TLS key file of the certificate")
+	if err := viper.BindPFlag(flagListenAddr, cmd.Flags().Lookup(flagListenAddr)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagTokenFile, cmd.Flags().Lookup(flagTokenFile)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagTLSCert, cmd.Flags().Lookup(flagTLSCert)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagTLSKey, cmd.Flags().Lookup(flagTLSKey)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func pathFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().StringP(flagPath, "p", "", "specify the path to relay over")
 	if err := viper.BindPFlag(flagPath, cmd.Flags().Lookup(flagPath)); err != nil {
@@ -203,8 +239,10 @@ func urlFlag(cmd *cobra.Command) *cobra.Command {
 }
 
 func strategyFlag(cmd *cobra.Command) *cobra.Command {
-	cmd.Flags().StringP(flagMaxTxSize, "s", "2", "strategy of path to generate of the messages in a relay transaction")
-	cmd.Flags().StringP(flagMaxMsgLength, "l", "5", "maximum number of messages in a relay transaction")
+	cmd.Flags().StringP(flagMaxTxSize, "s", "2",
+		"maximum size of the messages in a relay transaction in MB, overrides the path's max-tx-size option")
+	cmd.Flags().StringP(flagMaxMsgLength, "l", "5",
+		"maximum number of messages in a relay transaction, overrides the path's max-msg-length option")
 	if err := viper.BindPFlag(flagMaxTxSize, cmd.Flags().Lookup(flagMaxTxSize)); err != nil {
 		panic(err)
 	}
@@ -248,6 +286,44 @@ func updateTimeFlags(cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func sweepIntervalFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagSweepInterval, time.Minute,
+		"interval between sweeps for unrelayed packets and acknowledgements, 0 disables sweeping")
+	if err := viper.BindPFlag(flagSweepInterval, cmd.Flags().Lookup(flagSweepInterval)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func healthAddrFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagHealthAddr, "", "address to serve the relayer health status on, e.g. 0.0.0.0:5183")
+	if err := viper.BindPFlag(flagHealthAddr, cmd.Flags().Lookup(flagHealthAddr)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func shutdownTimeoutFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagShutdownTimeout, 30*time.Second,
+		"how long to wait for in-flight relays to complete after a shutdown signal")
+	if err := viper.BindPFlag(flagShutdownTimeout, cmd.Flags().Lookup(flagShutdownTimeout)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func startPathsFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringSlice(flagPaths, []string{}, "comma separated names of the paths to relay over")
+	cmd.Flags().Bool(flagAll, false, "relay over all configured paths")
+	if err := viper.BindPFlag(flagPaths, cmd.Flags().Lookup(flagPaths)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagAll, cmd.Flags().Lookup(flagAll)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func clientParameterFlags(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().BoolP(flagUpdateAfterExpiry, "e", true,
 		"allow governance to update the client if expiry occurs")
@@ -269,3 +345,65 @@ func overrideFlag(cmd *cobra.Command) *cobra.Command {
 	}
 	return cmd
 }
+
+func grantFeesFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagGranter, "",
+		"name of the key that grants the allowance, defaults to the key of the chain's fee-granter")
+	cmd.Flags().String(flagSpendLimit, "", "maximum amount of fees the grantee can spend, unlimited if empty")
+	cmd.Flags().Duration(flagExpiration, 0, "how long the allowance is valid for, never expires if zero")
+	if err := viper.BindPFlag(flagGranter, cmd.Flags().Lookup(flagGranter)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagSpendLimit, cmd.Flags().Lookup(flagSpendLimit)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagExpiration, cmd.Flags().Lookup(flagExpiration)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+// generateOnlyFlags registers --generate-only and --output-dir on cmd and all its subcommands
+func generateOnlyFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.PersistentFlags().BoolVar(&generateOnly, flagGenerateOnly, false,
+		"write unsigned transactions to files for offline signing instead of broadcasting them")
+	cmd.PersistentFlags().StringVar(&outputDir, flagOutputDir, ".",
+		"directory the unsigned <chain-id>-<n>.json transactions are written to")
+	if err := viper.BindPFlag(flagGenerateOnly, cmd.PersistentFlags().Lookup(flagGenerateOnly)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagOutputDir, cmd.PersistentFlags().Lookup(flagOutputDir)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func grantAuthzFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagGranter, "",
+		"name of the key that grants the authorizations, defaults to the key of the chain's authz-granter")
+	cmd.Flags().Duration(flagExpiration, 365*24*time.Hour, "how long the authorizations are valid for")
+	if err := viper.BindPFlag(flagGranter, cmd.Flags().Lookup(flagGranter)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagExpiration, cmd.Flags().Lookup(flagExpiration)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func lightInitFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Int64(flags.FlagHeight, 0, "height of the light block to trust")
+	cmd.Flags().String(flagHash, "", "hex encoded hash of the light block to trust")
+	cmd.Flags().BoolP(flagForce, "f", false,
+		"trust the latest light block of the chain's RPC endpoint instead of a given height and hash")
+	if err := viper.BindPFlag(flags.FlagHeight, cmd.Flags().Lookup(flags.FlagHeight)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagHash, cmd.Flags().Lookup(flagHash)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagForce, cmd.Flags().Lookup(flagForce)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
@@ -20,11 +20,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"strings"
+	"time"
 
 	ckeys "github.com/cosmos/cosmos-sdk/client/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/relayer/helpers"
+	"github.com/cosmos/relayer/relayer"
 	"github.com/spf13/cobra"
 )
 
@@ -47,6 +51,7 @@ func keysCmd() *cobra.Command {
 	cmd.AddCommand(keysListCmd())
 	cmd.AddCommand(keysShowCmd())
 	cmd.AddCommand(keysExportCmd())
+	cmd.AddCommand(keysServeSignerCmd())
 
 	return cmd
 }
@@ -227,7 +232,17 @@ $ %s k l ibc-1`, appName, appName)),
 				return err
 			}
 
+			// keys in the chain's signing pool also show their txs pending in the mempool
+			pending, err := chain.QueryPendingTxs()
+			if err != nil {
+				chain.Error(fmt.Errorf("failed to query pending txs: %w", err))
+			}
+
 			for d, i := range info {
+				if n, ok := pending[i.GetName()]; ok {
+					fmt.Printf("key(%d): %s -> %s (pool, pending txs: %d)\n", d, i.GetName(), i.GetAddress().String(), n)
+					continue
+				}
 				fmt.Printf("key(%d): %s -> %s\n", d, i.GetName(), i.GetAddress().String())
 			}
 
@@ -314,3 +329,102 @@ $ %s k e ibc-2 testkey`, appName, appName)),
 
 	return cmd
 }
+
+// keysServeSignerCmd respresents the `keys serve-signer` command
+func keysServeSignerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve-signer [[chain-id]...]",
+		Short: "serves the keys of the given chains, all chains if none are given, to remote signers",
+		Long: strings.TrimSpace(`Run a reference signer daemon that signs transactions with the keys of the local
+keyring. Relayers with a chain's signer set to the daemon's URL send it the bytes to sign
+instead of holding the keys themselves.
+
+Threat model: the daemon keeps the private keys off the relay hosts, so a compromised
+relay host can't take the keys with it. Whoever holds the token can still have the
+daemon sign, so:
+  - every request must carry the token in --token-file, which relayers read from the
+    file in their chain's signer-token-file;
+  - only relay transactions are signed: packet, acknowledgement and timeout msgs and
+    client updates, directly or in authz MsgExecs. A stolen token can't be used to send
+    the funds of a key, but it can spend them on fees;
+  - the daemon listens on localhost unless it is given a TLS certificate, the token
+    would otherwise cross the network in the clear.
+Handshakes, client creation and transfers must be signed with a local keyring.`),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys serve-signer --token-file ~/.relayer/signer-token
+$ %s k serve-signer ibc-0 ibc-1 -l 0.0.0.0:5183 --token-file ./token --tls-cert cert.pem --tls-key key.pem`,
+			appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chains := config.Chains
+			if len(args) > 0 {
+				chains = nil
+				for _, chainID := range args {
+					c, err := config.Chains.Get(chainID)
+					if err != nil {
+						return err
+					}
+					chains = append(chains, c)
+				}
+			}
+			if len(chains) == 0 {
+				return fmt.Errorf("no chains configured")
+			}
+
+			listenAddr, err := cmd.Flags().GetString(flagListenAddr)
+			if err != nil {
+				return err
+			}
+			tokenFile, err := cmd.Flags().GetString(flagTokenFile)
+			if err != nil {
+				return err
+			}
+			if tokenFile == "" {
+				return fmt.Errorf("pass --%s, requests are authenticated with the token it holds", flagTokenFile)
+			}
+			token, err := relayer.ReadSignerToken(tokenFile)
+			if err != nil {
+				return err
+			}
+			certFile, err := cmd.Flags().GetString(flagTLSCert)
+			if err != nil {
+				return err
+			}
+			keyFile, err := cmd.Flags().GetString(flagTLSKey)
+			if err != nil {
+				return err
+			}
+			if (certFile == "") != (keyFile == "") {
+				return fmt.Errorf("pass both --%s and --%s", flagTLSCert, flagTLSKey)
+			}
+			if certFile == "" && !isLoopback(listenAddr) {
+				return fmt.Errorf("can't listen on %s without TLS, pass --%s and --%s", listenAddr, flagTLSCert, flagTLSKey)
+			}
+
+			srv := &http.Server{
+				Handler:      relayer.SignerDaemon(token, chains...),
+				Addr:         listenAddr,
+				WriteTimeout: 15 * time.Second,
+				ReadTimeout:  15 * time.Second,
+			}
+			chains[0].Log(fmt.Sprintf("Serving keys of %d chains to remote signers on %s...", len(chains), listenAddr))
+			if certFile != "" {
+				return srv.ListenAndServeTLS(certFile, keyFile)
+			}
+			return srv.ListenAndServe()
+		},
+	}
+	return signerDaemonFlags(cmd)
+}
+
+// isLoopback returns true if the host of addr is localhost or a loopback IP
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
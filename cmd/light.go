@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func lightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "light",
+		Aliases: []string{"l"},
+		Short:   "manage the light client databases of the configured chains",
+		Long: strings.TrimSpace(`Each chain can have a light client database under the relayer home that stores
+the light blocks the relayer trusts. Once it is initialized, the headers the relayer fetches
+are verified by bisection from the last trusted light block, so a node serving forged headers
+is detected instead of trusted.`),
+	}
+
+	cmd.AddCommand(
+		lightInitCmd(),
+		lightShowCmd(),
+		lightDeleteCmd(),
+	)
+
+	return cmd
+}
+
+func lightInitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "init [chain-id]",
+		Aliases: []string{"i"},
+		Short:   "initialize the light client database of a chain from a trusted height and hash",
+		Args:    cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s light init ibc-0 --height 1406 --hash <hash>
+$ %s light init ibc-0 --force
+$ %s l i ibc-0 -f`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			height, err := cmd.Flags().GetInt64(flags.FlagHeight)
+			if err != nil {
+				return err
+			}
+			hashStr, err := cmd.Flags().GetString(flagHash)
+			if err != nil {
+				return err
+			}
+			force, err := cmd.Flags().GetBool(flagForce)
+			if err != nil {
+				return err
+			}
+
+			var hash []byte
+			if force {
+				if height != 0 || hashStr != "" {
+					return fmt.Errorf("can't pass --force with --height and --hash")
+				}
+			} else {
+				if height == 0 || hashStr == "" {
+					return fmt.Errorf("pass both --height and --hash of a trusted light block, or --force to trust the latest one")
+				}
+				if hash, err = hex.DecodeString(hashStr); err != nil {
+					return fmt.Errorf("invalid hash %s: %w", hashStr, err)
+				}
+			}
+
+			lb, err := chain.InitLight(height, hash)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("light client database of %s initialized at height %d with hash %s\n",
+				chain.ChainID, lb.Height, lb.Hash())
+			return nil
+		},
+	}
+	return lightInitFlags(cmd)
+}
+
+func lightShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "show [chain-id]",
+		Aliases: []string{"s"},
+		Short:   "show the trusted light blocks in the light client database of a chain",
+		Args:    cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s light show ibc-0
+$ %s light show ibc-0 --json
+$ %s l s ibc-0 --yaml`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+			yml, err := cmd.Flags().GetBool(flagYAML)
+			if err != nil {
+				return err
+			}
+			if yml && jsn {
+				return fmt.Errorf("can't pass both --json and --yaml, must pick one")
+			}
+
+			st, err := chain.LightStatus()
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case yml:
+				out, err := yaml.Marshal(st)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+			case jsn:
+				out, err := json.Marshal(st)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+			default:
+				fmt.Printf(`chain-id:     %s
+path:         %s
+first height: %d
+last height:  %d
+last hash:    %s
+last time:    %s
+`, st.ChainID, st.Path, st.FirstHeight, st.LastHeight, st.LastHash, st.LastTime.Format(time.RFC3339))
+			}
+			return nil
+		},
+	}
+	return yamlFlag(jsonFlag(cmd))
+}
+
+func lightDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete [chain-id]",
+		Aliases: []string{"d"},
+		Short:   "delete the light client database of a chain, its headers are then fetched unverified",
+		Args:    cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s light delete ibc-0
+$ %s l d ibc-0`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			if err = chain.DeleteLight(); err != nil {
+				return err
+			}
+
+			fmt.Printf("light client database of %s deleted\n", chain.ChainID)
+			return nil
+		},
+	}
+	return cmd
+}
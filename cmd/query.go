@@ -30,9 +30,12 @@ func queryCmd() *cobra.Command {
 	cmd.AddCommand(
 		queryUnrelayedPackets(),
 		queryUnrelayedAcknowledgements(),
+		queryFailedPackets(),
 		flags.LineBreak,
 		queryAccountCmd(),
 		queryBalanceCmd(),
+		queryFeeAllowancesCmd(),
+		queryAuthzGrantsCmd(),
 		queryHeaderCmd(),
 		queryNodeStateCmd(),
 		queryValSetAtHeightCmd(),
@@ -194,7 +197,7 @@ $ %s q acc ibc-1`,
 				return err
 			}
 
-			res, err := types.NewQueryClient(chain.CLIContext(0)).Account(
+			res, err := types.NewQueryClient(chain.QueryConn(0)).Account(
 				context.Background(),
 				&types.QueryAccountRequest{
 					Address: addr.String(),
@@ -211,6 +214,76 @@ $ %s q acc ibc-1`,
 	return cmd
 }
 
+func queryFeeAllowancesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "fee-allowances [chain-id]",
+		Aliases: []string{"allowances"},
+		Short:   "query the fee allowance left for each relayer key from the chain's fee-granter",
+		Args:    cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s query fee-allowances ibc-0
+$ %s q allowances ibc-1`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			allowances, err := chain.QueryFeeAllowances()
+			if err != nil {
+				return err
+			}
+
+			out, err := json.Marshal(allowances)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(out))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func queryAuthzGrantsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "authz-grants [chain-id]",
+		Aliases: []string{"grants"},
+		Short:   "query the authz grants each relayer key has from the chain's authz-granter",
+		Args:    cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s query authz-grants ibc-0
+$ %s q grants ibc-1`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chain, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			grants, err := chain.QueryAuthzGrants()
+			if err != nil {
+				return err
+			}
+
+			out, err := json.Marshal(grants)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(out))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
 func queryBalanceCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "balance [chain-id] [[key-name]]",
@@ -750,7 +823,7 @@ $ %s query unrelayed-pkts demo-path`,
 				return err
 			}
 
-			sp, err := strategy.UnrelayedSequences(c[src], c[dst])
+			sp, err := strategy.UnrelayedSequences(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
@@ -804,7 +877,7 @@ $ %s query unrelayed-acks demo-path`,
 				return err
 			}
 
-			sp, err := strategy.UnrelayedAcknowledgements(c[src], c[dst])
+			sp, err := strategy.UnrelayedAcknowledgements(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
@@ -821,3 +894,37 @@ $ %s query unrelayed-acks demo-path`,
 
 	return cmd
 }
+
+func queryFailedPackets() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "failed-packets [path]",
+		Aliases: []string{"failed"},
+		Short:   "query the packets and acknowledgements on a given path that failed to relay and are queued for retry",
+		Args:    cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s q failed-packets demo-path
+$ %s query failed demo-path`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := config.Paths.Get(args[0]); err != nil {
+				return err
+			}
+
+			fps, err := relayer.NewFailedPacketQueue(homePath, args[0]).List()
+			if err != nil {
+				return err
+			}
+
+			out, err := json.Marshal(fps)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(out))
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bufio"
+	"fmt"
 	"os"
 	"strings"
 
@@ -34,10 +35,15 @@ const (
 var (
 	homePath    string
 	debug       bool
+	dryRun      bool
 	config      *Config
 	defaultHome = os.ExpandEnv("$HOME/.relayer")
 	appName     = "rly"
 
+	// set by the --generate-only and --output-dir flags of the tx command
+	generateOnly bool
+	outputDir    string
+
 	// Default identifiers for dummy usage
 	dcli = "defaultclientid"
 	dcon = "defaultconnectionid"
@@ -67,9 +73,11 @@ func NewRootCmd() *cobra.Command {
 		return initConfig(rootCmd)
 	}
 
-	// Register top level flags --home and --debug
+	// Register top level flags --home, --debug and --dry-run
 	rootCmd.PersistentFlags().StringVar(&homePath, flags.FlagHome, defaultHome, "set home directory")
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "debug output")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, flagDryRun, false,
+		"simulate transactions and print their msgs, gas and fees instead of broadcasting them")
 
 	if err := viper.BindPFlag(flags.FlagHome, rootCmd.PersistentFlags().Lookup(flags.FlagHome)); err != nil {
 		panic(err)
@@ -77,12 +85,16 @@ func NewRootCmd() *cobra.Command {
 	if err := viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug")); err != nil {
 		panic(err)
 	}
+	if err := viper.BindPFlag(flagDryRun, rootCmd.PersistentFlags().Lookup(flagDryRun)); err != nil {
+		panic(err)
+	}
 
 	// Register subcommands
 	rootCmd.AddCommand(
 		configCmd(),
 		chainsCmd(),
 		pathsCmd(),
+		lightCmd(),
 		flags.LineBreak,
 		keysCmd(),
 		flags.LineBreak,
@@ -109,7 +121,16 @@ func Execute() {
 	rootCmd := NewRootCmd()
 	rootCmd.SilenceUsage = true
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+
+	// the light client databases are kept open while the command runs
+	if config != nil {
+		if closeErr := config.Chains.Close(); closeErr != nil {
+			fmt.Fprintln(os.Stderr, closeErr)
+		}
+	}
+
+	if err != nil {
 		os.Exit(1)
 	}
 }
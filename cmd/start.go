@@ -17,16 +17,20 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/avast/retry-go"
 	"github.com/cosmos/relayer/relayer"
+	"github.com/gorilla/mux"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
@@ -39,88 +43,269 @@ func startCmd() *cobra.Command {
 		Use:     "start [path-name]",
 		Aliases: []string{"st"},
 		Short:   "Start the listening relayer on a given path",
-		Args:    cobra.ExactArgs(1),
+		Long: strings.TrimSpace(`Start the listening relayer on a given path, or on several paths at once with
+--paths or --all. Each chain is subscribed to once and its events are shared by all the paths relaying
+over it.`),
+		Args: cobra.MaximumNArgs(1),
 		Example: strings.TrimSpace(fmt.Sprintf(`
 $ %s start demo-path --max-msgs 3
-$ %s start demo-path2 --max-tx-size 10`, appName, appName)),
+$ %s start demo-path2 --max-tx-size 10
+$ %s start demo-path --sweep-interval 30s
+$ %s start demo-path --health-addr 0.0.0.0:5183
+$ %s start demo-path --shutdown-timeout 1m
+$ %s start --paths demo-path,demo-path2
+$ %s start --all`, appName, appName, appName, appName, appName, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, src, dst, err := config.ChainsFromPath(args[0])
+			paths, err := startPathNames(cmd, args)
 			if err != nil {
 				return err
 			}
 
-			if err = ensureKeysExist(c); err != nil {
-				return err
-			}
+			return startRelayer(cmd, paths)
+		},
+	}
+	return startPathsFlags(shutdownTimeoutFlag(healthAddrFlag(sweepIntervalFlag(strategyFlag(updateTimeFlags(cmd))))))
+}
 
-			path := config.Paths.MustGet(args[0])
-			strategy, err := GetStrategyWithOptions(cmd, path.MustGetStrategy())
-			if err != nil {
+// startPathNames returns the names of the paths to start from either the args, --paths or --all
+func startPathNames(cmd *cobra.Command, args []string) ([]string, error) {
+	paths, err := cmd.Flags().GetStringSlice(flagPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := cmd.Flags().GetBool(flagAll)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(args) > 0 && (len(paths) > 0 || all), len(paths) > 0 && all:
+		return nil, fmt.Errorf("must pick one of [path-name], --%s or --%s", flagPaths, flagAll)
+	case len(args) > 0:
+		return args, nil
+	case len(paths) > 0:
+		return paths, nil
+	case all:
+		for name := range config.Paths {
+			paths = append(paths, name)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no paths configured")
+		}
+		sort.Strings(paths)
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("must specify a [path-name], --%s or --%s", flagPaths, flagAll)
+	}
+}
+
+// pathRelayer holds everything needed to relay over a single path
+type pathRelayer struct {
+	name     string
+	src, dst *relayer.Chain
+	strategy relayer.Strategy
+}
+
+// startRelayer relays over the given paths until a SIGINT or SIGTERM is received or the
+// clients of every path can no longer be updated
+func startRelayer(cmd *cobra.Command, pathNames []string) error {
+	sweepInterval, err := cmd.Flags().GetDuration(flagSweepInterval)
+	if err != nil {
+		return err
+	}
+
+	healthAddr, err := cmd.Flags().GetString(flagHealthAddr)
+	if err != nil {
+		return err
+	}
+
+	shutdownTimeout, err := cmd.Flags().GetDuration(flagShutdownTimeout)
+	if err != nil {
+		return err
+	}
+
+	thresholdTime := viper.GetDuration(flagThresholdTime)
+
+	// set up every path before starting any, each path gets its own copies of its chains
+	// so paths over the same chain don't share path ends
+	var (
+		relayers []*pathRelayer
+		chainIDs []string
+		chains   = make(map[string]*relayer.Chain)
+	)
+	for _, name := range pathNames {
+		path, err := config.Paths.Get(name)
+		if err != nil {
+			return err
+		}
+
+		src, dst, err := config.PathChains(name)
+		if err != nil {
+			return err
+		}
+
+		if err = ensureKeysExist(map[string]*relayer.Chain{src.ChainID: src, dst.ChainID: dst}); err != nil {
+			return err
+		}
+
+		strategy, err := GetStrategyWithOptions(cmd, path.MustGetStrategy())
+		if err != nil {
+			return err
+		}
+
+		// packets that fail to relay are queued under the home directory and retried
+		if ds, ok := strategy.(relayer.DeadLetterStrategy); ok {
+			ds.SetFailedPacketQueue(relayer.NewFailedPacketQueue(homePath, name))
+		}
+
+		if relayer.SendToController != nil {
+			action := relayer.PathAction{
+				Path: path,
+				Type: "RELAYER_PATH_START",
+			}
+			cont, err := relayer.ControllerUpcall(&action)
+			if !cont {
 				return err
 			}
+		}
 
-			if relayer.SendToController != nil {
-				action := relayer.PathAction{
-					Path: path,
-					Type: "RELAYER_PATH_START",
-				}
-				cont, err := relayer.ControllerUpcall(&action)
-				if !cont {
+		for _, chainID := range []string{src.ChainID, dst.ChainID} {
+			if _, ok := chains[chainID]; !ok {
+				if chains[chainID], err = config.Chains.Get(chainID); err != nil {
 					return err
 				}
+				chainIDs = append(chainIDs, chainID)
 			}
+		}
 
-			done, err := relayer.RunStrategy(c[src], c[dst], strategy)
-			if err != nil {
+		relayers = append(relayers, &pathRelayer{name: name, src: src, dst: dst, strategy: strategy})
+	}
+
+	// ctx is cancelled on SIGINT or SIGTERM, which stops the relayer from picking up new work
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	go trapSignal(ctx, cancel)
+
+	// every chain is subscribed to once, through its configured chain
+	subscribed := make([]*relayer.Chain, len(chainIDs))
+	for i, chainID := range chainIDs {
+		subscribed[i] = chains[chainID]
+	}
+	hub := relayer.NewEventHub(subscribed...)
+
+	var (
+		eg     errgroup.Group
+		drains []func(context.Context) error
+	)
+	for _, pr := range relayers {
+		pr := pr
+
+		drain, err := hub.RunStrategy(ctx, pr.src, pr.dst, pr.strategy, sweepInterval)
+		if err != nil {
+			cancel()
+			return err
+		}
+		drains = append(drains, drain)
+
+		// keep the clients of the path up to date, a path whose clients can't be updated
+		// stops updating them without affecting the others
+		eg.Go(func() error {
+			if err := autoUpdateClients(ctx, pr.src, pr.dst, thresholdTime); err != nil {
+				err = fmt.Errorf("path %s: %w", pr.name, err)
+				pr.src.Error(err)
 				return err
 			}
+			return nil
+		})
+	}
+
+	if healthAddr != "" {
+		go serveHealth(healthAddr, subscribed...)
+	}
 
-			thresholdTime := viper.GetDuration(flagThresholdTime)
-
-			eg := new(errgroup.Group)
-			eg.Go(func() error {
-				for {
-					var timeToExpiry time.Duration
-					if err := retry.Do(func() error {
-						timeToExpiry, err = UpdateClientsFromChains(c[src], c[dst], thresholdTime)
-						if err != nil {
-							return err
-						}
-						return nil
-					}, retry.Attempts(5), retry.Delay(time.Millisecond*500), retry.LastErrorOnly(true)); err != nil {
-						return err
-					}
-					time.Sleep(timeToExpiry - thresholdTime)
+	err = eg.Wait()
+
+	// stop listening for new events, then give in-flight relays a chance to finish
+	cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	for i, drain := range drains {
+		if drainErr := drain(shutdownCtx); drainErr != nil {
+			relayers[i].src.Error(fmt.Errorf("in-flight relays on path %s did not finish within %s: %w",
+				relayers[i].name, shutdownTimeout, drainErr))
+		}
+	}
+
+	return err
+}
+
+// autoUpdateClients updates the clients of src and dst before they expire until ctx is done
+func autoUpdateClients(ctx context.Context, src, dst *relayer.Chain, thresholdTime time.Duration) error {
+	for {
+		var (
+			timeToExpiry time.Duration
+			err          error
+		)
+		if err = retry.Do(func() error {
+			timeToExpiry, err = UpdateClientsFromChains(ctx, src, dst, thresholdTime)
+			if err != nil {
+				if ctx.Err() != nil {
+					return retry.Unrecoverable(err)
 				}
-			})
-			if err = eg.Wait(); err != nil {
 				return err
 			}
-
-			trapSignal(done)
 			return nil
-		},
+		}, retry.Attempts(5), retry.Delay(time.Millisecond*500), retry.LastErrorOnly(true)); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-time.After(timeToExpiry - thresholdTime):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// serveHealth serves the subscription status of the given chains on /health
+func serveHealth(addr string, chains ...*relayer.Chain) {
+	r := mux.NewRouter()
+	r.HandleFunc("/health", relayer.HealthHandler(chains...)).Methods("GET")
+	r.HandleFunc("/light-cache", relayer.LightCacheHandler).Methods("GET")
+	srv := &http.Server{
+		Handler:      r,
+		Addr:         addr,
+		WriteTimeout: 15 * time.Second,
+		ReadTimeout:  15 * time.Second,
+	}
+	chains[0].Log(fmt.Sprintf("Serving health status on %s/health...", addr))
+	if err := srv.ListenAndServe(); err != nil {
+		chains[0].Error(err)
 	}
-	return strategyFlag(updateTimeFlags(cmd))
 }
 
-// trap signal waits for a SIGINT or SIGTERM and then sends down the done channel
-func trapSignal(done func()) {
+// trapSignal waits for a SIGINT or SIGTERM and then calls the cancel func. It returns
+// without cancelling if ctx is done first.
+func trapSignal(ctx context.Context, cancel func()) {
 	sigCh := make(chan os.Signal, 1)
 
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
 	// wait for a signal
-	sig := <-sigCh
-	fmt.Println("Signal Received", sig.String())
-	close(sigCh)
-
-	// call the cleanup func
-	done()
+	select {
+	case sig := <-sigCh:
+		fmt.Println("Signal Received", sig.String())
+		cancel()
+	case <-ctx.Done():
+	}
 }
 
 // UpdateClientsFromChains takes src, dst chains, threshold time and update clients based on expiry time
-func UpdateClientsFromChains(src, dst *relayer.Chain, thresholdTime time.Duration) (time.Duration, error) {
+func UpdateClientsFromChains(ctx context.Context, src, dst *relayer.Chain, thresholdTime time.Duration) (time.Duration, error) {
 	var (
 		srcTimeExpiry, dstTimeExpiry time.Duration
 		err                          error
@@ -128,11 +313,11 @@ func UpdateClientsFromChains(src, dst *relayer.Chain, thresholdTime time.Duratio
 
 	eg := new(errgroup.Group)
 	eg.Go(func() error {
-		srcTimeExpiry, err = relayer.AutoUpdateClient(src, dst, thresholdTime)
+		srcTimeExpiry, err = relayer.AutoUpdateClient(ctx, src, dst, thresholdTime)
 		return err
 	})
 	eg.Go(func() error {
-		dstTimeExpiry, err = relayer.AutoUpdateClient(dst, src, thresholdTime)
+		dstTimeExpiry, err = relayer.AutoUpdateClient(ctx, dst, src, thresholdTime)
 		return err
 	})
 	if err := eg.Wait(); err != nil {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -35,7 +36,10 @@ Most of these commands take a [path] argument. Make sure:
 		linkThenStartCmd(),
 		relayMsgsCmd(),
 		relayAcksCmd(),
+		retryFailedCmd(),
 		xfersend(),
+		grantFeesCmd(),
+		grantAuthzCmd(),
 		flags.LineBreak,
 		createClientsCmd(),
 		updateClientsCmd(),
@@ -47,9 +51,10 @@ Most of these commands take a [path] argument. Make sure:
 		rawTransactionCmd(),
 		flags.LineBreak,
 		sendCmd(),
+		broadcastCmd(),
 	)
 
-	return cmd
+	return generateOnlyFlags(cmd)
 }
 
 func sendCmd() *cobra.Command {
@@ -88,7 +93,7 @@ $ %s tx send testkey cosmos10yft4nc8tacpngwlpyq3u4t88y7qzc9xv0q4y8 10000uatom`,
 				return err
 			}
 
-			res, _, err := c.SendMsg(msg)
+			res, _, err := c.SendMsg(context.Background(), msg)
 			if err != nil {
 				return err
 			}
@@ -100,6 +105,210 @@ $ %s tx send testkey cosmos10yft4nc8tacpngwlpyq3u4t88y7qzc9xv0q4y8 10000uatom`,
 	return cmd
 }
 
+func broadcastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broadcast [chain-id] [signed.json]",
+		Short: "broadcast a transaction that was generated with --generate-only and signed offline",
+		Long: strings.TrimSpace(`Broadcast a signed transaction to a configured chain and wait for it to be
+committed. The transaction is usually one written by a tx or raw command run with
+--generate-only and signed offline, e.g. by the members of a multisig account.
+
+The client, connection and channel handshakes write only their next step when they are
+run with --generate-only. Once it is signed and broadcast, run the same command again
+to write the step after it.`,
+		),
+		Args: cobra.ExactArgs(2),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx connection demo-path --generate-only --output-dir ./unsigned
+$ %s tx broadcast ibc-0 ./signed/ibc-0-1.json
+$ %s tx connection demo-path --generate-only --output-dir ./unsigned`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			res, _, err := c.BroadcastSignedTx(cmd.Context(), args[1])
+			if err != nil {
+				return err
+			}
+
+			return c.Print(res, false, true)
+		},
+	}
+	return cmd
+}
+
+func grantFeesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant-fees [chain-id] [grantee]",
+		Short: "grant a relayer key an allowance to have its fees paid by a fee granter account",
+		Long: strings.TrimSpace(`Grant the grantee, a key name or address, an x/feegrant allowance to pay its
+transaction fees from the granter's account. With the granter set as the chain's fee-granter the relayer
+keys don't need to hold any funds.`),
+		Args: cobra.ExactArgs(2),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx grant-fees ibc-0 relayer2
+$ %s tx grant-fees ibc-0 cosmos10yft4nc8tacpngwlpyq3u4t88y7qzc9xv0q4y8 --granter treasury
+$ %s tx grant-fees ibc-0 relayer2 --spend-limit 1000000stake --expiration 720h`,
+			appName, appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			granterKey, err := cmd.Flags().GetString(flagGranter)
+			if err != nil {
+				return err
+			}
+			if granterKey == "" {
+				granter, err := c.GetFeeGranter()
+				if err != nil {
+					return err
+				}
+				if granterKey, err = granterKeyName(c, granter, "fee-granter"); err != nil {
+					return err
+				}
+			}
+			granter, err := c.Keybase.Key(granterKey)
+			if err != nil {
+				return err
+			}
+
+			grantee, err := keyOrAddress(c, args[1])
+			if err != nil {
+				return err
+			}
+
+			limit, err := cmd.Flags().GetString(flagSpendLimit)
+			if err != nil {
+				return err
+			}
+			spendLimit, err := sdk.ParseCoinsNormalized(limit)
+			if err != nil {
+				return err
+			}
+
+			validFor, err := cmd.Flags().GetDuration(flagExpiration)
+			if err != nil {
+				return err
+			}
+			var expiration *time.Time
+			if validFor > 0 {
+				exp := time.Now().Add(validFor)
+				expiration = &exp
+			}
+
+			msg, err := c.MsgGrantFeeAllowance(granter.GetAddress(), grantee, spendLimit, expiration)
+			if err != nil {
+				return err
+			}
+
+			// the granter signs the grant
+			res, _, err := c.WithKey(granterKey).SendMsg(cmd.Context(), msg)
+			if err != nil {
+				return err
+			}
+
+			return c.Print(res, false, true)
+		},
+	}
+
+	return grantFeesFlags(cmd)
+}
+
+func grantAuthzCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant-authz [chain-id] [grantee]",
+		Short: "grant a relayer key authorization to relay on behalf of an authz granter account",
+		Long: strings.TrimSpace(`Grant the grantee, a key name or address, x/authz authorizations to send the msgs
+needed for relaying on behalf of the granter. With the granter set as the chain's authz-granter the relayer
+sends its msgs wrapped in a MsgExec, so they are accounted to the granter.`),
+		Args: cobra.ExactArgs(2),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s tx grant-authz ibc-0 relayer2
+$ %s tx grant-authz ibc-0 cosmos10yft4nc8tacpngwlpyq3u4t88y7qzc9xv0q4y8 --granter partner --expiration 720h`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := config.Chains.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			granterKey, err := cmd.Flags().GetString(flagGranter)
+			if err != nil {
+				return err
+			}
+			if granterKey == "" {
+				granter, err := c.GetAuthzGranter()
+				if err != nil {
+					return err
+				}
+				if granterKey, err = granterKeyName(c, granter, "authz-granter"); err != nil {
+					return err
+				}
+			}
+			granter, err := c.Keybase.Key(granterKey)
+			if err != nil {
+				return err
+			}
+
+			grantee, err := keyOrAddress(c, args[1])
+			if err != nil {
+				return err
+			}
+
+			validFor, err := cmd.Flags().GetDuration(flagExpiration)
+			if err != nil {
+				return err
+			}
+
+			msgs, err := c.MsgGrantRelayAuthz(granter.GetAddress(), grantee, time.Now().Add(validFor))
+			if err != nil {
+				return err
+			}
+
+			// the granter signs the grants
+			res, _, err := c.WithKey(granterKey).SendMsgs(cmd.Context(), msgs)
+			if err != nil {
+				return err
+			}
+
+			return c.Print(res, false, true)
+		},
+	}
+
+	return grantAuthzFlags(cmd)
+}
+
+// granterKeyName returns the name of the key of the granter configured in the chain's field
+func granterKeyName(c *relayer.Chain, granter sdk.AccAddress, field string) (string, error) {
+	if granter == nil {
+		return "", fmt.Errorf("chain %s has no %s configured, pass --%s", c.ChainID, field, flagGranter)
+	}
+
+	info, err := c.Keybase.KeyByAddress(granter)
+	if err != nil {
+		return "", fmt.Errorf("no key for the %s of chain %s, pass --%s: %w", field, c.ChainID, flagGranter, err)
+	}
+	return info.GetName(), nil
+}
+
+// keyOrAddress returns the address of the key with the given name, or the address itself
+func keyOrAddress(c *relayer.Chain, keyOrAddr string) (sdk.AccAddress, error) {
+	if c.KeyExists(keyOrAddr) {
+		info, err := c.Keybase.Key(keyOrAddr)
+		if err != nil {
+			return nil, err
+		}
+		return info.GetAddress(), nil
+	}
+	return sdk.GetFromBech32(keyOrAddr, c.AccountPrefix)
+}
+
 func createClientsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "clients [path-name]",
@@ -302,7 +511,7 @@ $ %s tx conn demo-path --timeout 5s`,
 func closeChannelCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "channel-close [path-name]",
-		Short: "close a channel between two configured chains with a configured path",
+		Short: "close a channel between two configured chains with a configured path and time out its packets in flight",
 		Args:  cobra.ExactArgs(1),
 		Example: strings.TrimSpace(fmt.Sprintf(`
 $ %s transact channel-close demo-path
@@ -330,11 +539,20 @@ $ %s tx channel-close demo-path -o 3s`,
 				return err
 			}
 
-			return c[src].CloseChannel(c[dst], to)
+			// packets in flight are timed out with the path's strategy and packet filter
+			strategy, err := GetStrategyWithOptions(cmd, config.Paths.MustGet(args[0]).MustGetStrategy())
+			if err != nil {
+				return err
+			}
+			if ds, ok := strategy.(relayer.DeadLetterStrategy); ok {
+				ds.SetFailedPacketQueue(relayer.NewFailedPacketQueue(homePath, args[0]))
+			}
+
+			return c[src].CloseChannel(c[dst], to, strategy)
 		},
 	}
 
-	return timeoutFlag(cmd)
+	return strategyFlag(timeoutFlag(cmd))
 }
 
 func linkCmd() *cobra.Command {
@@ -442,6 +660,11 @@ networks with a configured path and then start the relayer on that path.`,
 $ %s transact link-then-start demo-path
 $ %s tx link-then-start demo-path --timeout 5s`, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// each run of a handshake only writes its next step, so linking would be retried forever
+			if generateOnly {
+				return fmt.Errorf("can't pass --%s: %w", flagGenerateOnly, relayer.ErrGenerateOnlyHandshake)
+			}
+
 			lCmd := linkCmd()
 
 			for err := lCmd.RunE(cmd, args); err != nil; err = lCmd.RunE(cmd, args) {
@@ -449,12 +672,11 @@ $ %s tx link-then-start demo-path --timeout 5s`, appName, appName)),
 				time.Sleep(1 * time.Second)
 			}
 
-			sCmd := startCmd()
-			return sCmd.RunE(cmd, args)
+			return startRelayer(cmd, args)
 		},
 	}
 
-	return overrideFlag(clientParameterFlags(strategyFlag(retryFlag(timeoutFlag(cmd)))))
+	return shutdownTimeoutFlag(healthAddrFlag(sweepIntervalFlag(overrideFlag(clientParameterFlags(strategyFlag(retryFlag(timeoutFlag(cmd))))))))
 }
 
 func relayMsgsCmd() *cobra.Command {
@@ -483,12 +705,12 @@ $ %s tx relay-pkts demo-path`,
 				return err
 			}
 
-			sp, err := strategy.UnrelayedSequences(c[src], c[dst])
+			sp, err := strategy.UnrelayedSequences(cmd.Context(), c[src], c[dst])
 			if err != nil {
 				return err
 			}
 
-			if err = strategy.RelayPackets(c[src], c[dst], sp); err != nil {
+			if err = strategy.RelayPackets(cmd.Context(), c[src], c[dst], sp); err != nil {
 				return err
 			}
 
@@ -527,15 +749,73 @@ $ %s tx relay-acks demo-path -l 3 -s 6`,
 
 			// sp.Src contains all sequences acked on SRC but acknowledgement not processed on DST
 			// sp.Dst contains all sequences acked on DST but acknowledgement not processed on SRC
-			sp, err := strategy.UnrelayedAcknowledgements(c[src], c[dst])
+			sp, err := strategy.UnrelayedAcknowledgements(cmd.Context(), c[src], c[dst])
+			if err != nil {
+				return err
+			}
+
+			if err = strategy.RelayAcknowledgements(cmd.Context(), c[src], c[dst], sp); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return strategyFlag(cmd)
+}
+
+func retryFailedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry-failed [path-name]",
+		Short: "requeue and relay the packets and acknowledgements on a given path that failed to relay",
+		Long: strings.TrimSpace(`Reset the backoff of every packet and acknowledgement in the failed packet
+queue of a given path and relay the ones that remain unrelayed. Anything that fails again stays queued
+and is retried by a running relayer.`,
+		),
+		Args: cobra.ExactArgs(1),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s transact retry-failed demo-path
+$ %s tx retry-failed demo-path -l 3 -s 6`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			if err = ensureKeysExist(c); err != nil {
+				return err
+			}
+
+			strategy, err := GetStrategyWithOptions(cmd, config.Paths.MustGet(args[0]).MustGetStrategy())
+			if err != nil {
+				return err
+			}
+
+			queue := relayer.NewFailedPacketQueue(homePath, args[0])
+			fps, err := queue.Requeue()
 			if err != nil {
 				return err
 			}
+			if len(fps) == 0 {
+				fmt.Printf("no failed packets queued on path %s\n", args[0])
+				return nil
+			}
 
-			if err = strategy.RelayAcknowledgements(c[src], c[dst], sp); err != nil {
+			packets, acks, err := relayer.RetryFailedPackets(cmd.Context(), c[src], c[dst], strategy, queue)
+			if err != nil {
 				return err
 			}
 
+			remaining, err := queue.List()
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("relayed %d packets and %d acknowledgements on path %s, %d remain queued\n",
+				packets, acks, args[0], len(remaining))
 			return nil
 		},
 	}
@@ -614,6 +894,11 @@ func ensureKeysExist(chains map[string]*relayer.Chain) error {
 		if _, err := v.GetAddress(); err != nil {
 			return err
 		}
+		for _, k := range v.KeyPool() {
+			if _, err := v.KeyAddress(k); err != nil {
+				return fmt.Errorf("key %s of chain %s's key pool doesn't exist", k, v.ChainID)
+			}
+		}
 	}
 
 	return nil
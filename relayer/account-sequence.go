@@ -0,0 +1,91 @@
+package relayer
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	sdkCtx "github.com/cosmos/cosmos-sdk/client"
+	tx "github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// accountSequence caches the account number and next sequence of a signing key so transactions
+// can be signed without querying the account first. The sequence is incremented for every
+// transaction that enters the mempool and resynced from chain when it turns out to be wrong.
+// mu must be held while signing and broadcasting a transaction.
+type accountSequence struct {
+	mu sync.Mutex
+
+	synced   bool
+	number   uint64
+	sequence uint64
+}
+
+// factory returns txf set to sign with the cached account number and sequence, querying them
+// from chain if they aren't synced
+func (as *accountSequence) factory(clientCtx sdkCtx.Context, txf tx.Factory) (tx.Factory, error) {
+	if !as.synced {
+		from := clientCtx.GetFromAddress()
+
+		if err := txf.AccountRetriever().EnsureExists(clientCtx, from); err != nil {
+			return txf, err
+		}
+
+		num, seq, err := txf.AccountRetriever().GetAccountNumberSequence(clientCtx, from)
+		if err != nil {
+			return txf, err
+		}
+
+		as.number, as.sequence, as.synced = num, seq, true
+	}
+
+	return txf.WithAccountNumber(as.number).WithSequence(as.sequence), nil
+}
+
+// increment advances the sequence after a transaction entered the mempool. mu must be held.
+func (as *accountSequence) increment() {
+	as.sequence++
+}
+
+// invalidate makes the next transaction query the account sequence from chain. mu must be held.
+func (as *accountSequence) invalidate() {
+	as.synced = false
+}
+
+// invalidateOnMismatch invalidates the sequence if err is an account sequence mismatch. mu must
+// be held.
+func (as *accountSequence) invalidateOnMismatch(err error) {
+	if isSequenceMismatch(nil, err) {
+		as.invalidate()
+	}
+}
+
+// resync makes the next transaction query the account sequence from chain
+func (as *accountSequence) resync() {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.invalidate()
+}
+
+// isSequenceMismatch returns true if the transaction was rejected because it wasn't signed with
+// the account's current sequence
+func isSequenceMismatch(res *sdk.TxResponse, err error) bool {
+	if err == nil && res != nil {
+		err = errorFromTxResponse(res)
+	}
+	return err != nil && strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error())
+}
+
+// errorFromTxResponse returns the error of a failed transaction response, nil if it succeeded
+func errorFromTxResponse(res *sdk.TxResponse) error {
+	if res.Code == 0 {
+		return nil
+	}
+	if res.Codespace == sdkerrors.ErrWrongSequence.Codespace() && res.Code == sdkerrors.ErrWrongSequence.ABCICode() {
+		return sdkerrors.ErrWrongSequence
+	}
+	return errors.New(res.RawLog)
+}
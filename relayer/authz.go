@@ -0,0 +1,201 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v2/modules/core/04-channel/types"
+)
+
+// RelayMsgTypeURLs are the msgs a relayer key needs to be granted to relay on behalf of an
+// authz granter
+var RelayMsgTypeURLs = []string{
+	sdk.MsgTypeURL(&clienttypes.MsgUpdateClient{}),
+	sdk.MsgTypeURL(&clienttypes.MsgSubmitMisbehaviour{}),
+	sdk.MsgTypeURL(&chantypes.MsgRecvPacket{}),
+	sdk.MsgTypeURL(&chantypes.MsgAcknowledgement{}),
+	sdk.MsgTypeURL(&chantypes.MsgTimeout{}),
+	sdk.MsgTypeURL(&chantypes.MsgTimeoutOnClose{}),
+}
+
+// AuthzGrants are the authz grants a key of a chain's key pool has from the chain's authz granter
+type AuthzGrants struct {
+	Key     string       `json:"key" yaml:"key"`
+	Grantee string       `json:"grantee" yaml:"grantee"`
+	Granter string       `json:"granter" yaml:"granter"`
+	Granted []AuthzGrant `json:"granted" yaml:"granted"`
+	Missing []string     `json:"missing,omitempty" yaml:"missing,omitempty"` // relay msgs that aren't granted
+	Error   string       `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// AuthzGrant is a msg granted by an authz grant
+type AuthzGrant struct {
+	MsgTypeURL string    `json:"msg-type-url" yaml:"msg-type-url"`
+	Expiration time.Time `json:"expiration" yaml:"expiration"`
+}
+
+// GetAuthzGranter returns the address of the account the chain's msgs are sent on behalf of,
+// nil if the signing keys send msgs on their own behalf
+func (c *Chain) GetAuthzGranter() (sdk.AccAddress, error) {
+	if c.AuthzGranter == "" {
+		return nil, nil
+	}
+	granter, err := sdk.GetFromBech32(c.AuthzGranter, c.AccountPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authz granter (%s) for chain %s: %w", c.AuthzGranter, c.ChainID, err)
+	}
+	return granter, nil
+}
+
+// MsgSigner returns the signer of the relay and client update msgs built for the chain: the authz
+// granter if one is configured, otherwise the address of the chain's key. Handshake, channel close
+// and client upgrade msgs aren't granted and are always signed by the chain's key.
+func (c *Chain) MsgSigner() string {
+	if c.AuthzGranter == "" {
+		return c.MustGetAddress()
+	}
+	done := c.UseSDKContext()
+	defer done()
+	return c.AuthzGranter
+}
+
+// wrapAuthz wraps the relay msgs signed by the chain's authz granter in MsgExecs sent by the
+// chain's key, keeping the msgs in order. Other msgs are left as they are.
+func (c *Chain) wrapAuthz(msgs []sdk.Msg) ([]sdk.Msg, error) {
+	granter, err := c.GetAuthzGranter()
+	if err != nil || granter == nil {
+		return msgs, err
+	}
+
+	// the granter's own key sends its msgs directly
+	grantee, err := c.GetAddress()
+	switch {
+	case err != nil:
+		return nil, err
+	case grantee.Equals(granter):
+		return msgs, nil
+	}
+
+	var (
+		out []sdk.Msg
+		run []sdk.Msg
+	)
+	flush := func() {
+		if len(run) > 0 {
+			exec := authz.NewMsgExec(grantee, run)
+			out = append(out, &exec)
+			run = nil
+		}
+	}
+	for _, msg := range msgs {
+		if isRelayMsg(msg) && c.signedBy(msg, granter) {
+			run = append(run, msg)
+			continue
+		}
+		flush()
+		out = append(out, msg)
+	}
+	flush()
+
+	return out, nil
+}
+
+// isRelayMsg returns true if msg is one of RelayMsgTypeURLs
+func isRelayMsg(msg sdk.Msg) bool {
+	return isRelayMsgTypeURL(sdk.MsgTypeURL(msg))
+}
+
+// isRelayMsgTypeURL returns true if url is one of RelayMsgTypeURLs
+func isRelayMsgTypeURL(url string) bool {
+	for _, u := range RelayMsgTypeURLs {
+		if u == url {
+			return true
+		}
+	}
+	return false
+}
+
+// signedBy returns true if addr is the only signer of msg
+func (c *Chain) signedBy(msg sdk.Msg, addr sdk.AccAddress) bool {
+	// signers are parsed with the chain's bech32 prefix
+	done := c.UseSDKContext()
+	defer done()
+
+	signers := msg.GetSigners()
+	return len(signers) == 1 && signers[0].Equals(addr)
+}
+
+// MsgGrantRelayAuthz returns the msgs that grant grantee authorization to send every msg in
+// RelayMsgTypeURLs on behalf of granter until expiration
+func (c *Chain) MsgGrantRelayAuthz(granter, grantee sdk.AccAddress, expiration time.Time) ([]sdk.Msg, error) {
+	done := c.UseSDKContext()
+	defer done()
+
+	var msgs []sdk.Msg
+	for _, url := range RelayMsgTypeURLs {
+		msg, err := authz.NewMsgGrant(granter, grantee, authz.NewGenericAuthorization(url), expiration)
+		if err != nil {
+			return nil, err
+		}
+		if err = msg.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// QueryAuthzGrants returns the grants from the chain's authz granter to every key of the chain's
+// key pool, along with the relay msgs each key is still missing a grant for
+func (c *Chain) QueryAuthzGrants() ([]AuthzGrants, error) {
+	granter, err := c.GetAuthzGranter()
+	switch {
+	case err != nil:
+		return nil, err
+	case granter == nil:
+		return nil, fmt.Errorf("chain %s has no authz-granter configured", c.ChainID)
+	}
+
+	out := []AuthzGrants{}
+	for _, k := range c.KeyPool() {
+		addr, err := c.KeyAddress(k)
+		if err != nil {
+			return nil, err
+		}
+
+		done := c.UseSDKContext()
+		ag := AuthzGrants{Key: k, Grantee: addr.String(), Granter: granter.String(), Granted: []AuthzGrant{}}
+		done()
+
+		res, err := authz.NewQueryClient(c.QueryConn(0)).Grants(context.Background(), &authz.QueryGrantsRequest{
+			Granter: ag.Granter,
+			Grantee: ag.Grantee,
+		})
+		if err != nil {
+			ag.Error = err.Error()
+			out = append(out, ag)
+			continue
+		}
+
+		granted := make(map[string]bool)
+		for _, g := range res.Grants {
+			var a authz.Authorization
+			if err = c.Encoding.InterfaceRegistry.UnpackAny(g.Authorization, &a); err != nil {
+				return nil, err
+			}
+			ag.Granted = append(ag.Granted, AuthzGrant{MsgTypeURL: a.MsgTypeURL(), Expiration: g.Expiration})
+			granted[a.MsgTypeURL()] = true
+		}
+		for _, url := range RelayMsgTypeURLs {
+			if !granted[url] {
+				ag.Missing = append(ag.Missing, url)
+			}
+		}
+		out = append(out, ag)
+	}
+	return out, nil
+}
@@ -2,7 +2,9 @@ package relayer
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -10,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	retry "github.com/avast/retry-go"
@@ -40,9 +43,18 @@ import (
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	libclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 )
 
 var (
+	// TxConfirmPollInterval is how often a transaction broadcast in sync mode is polled for
+	// until it is committed
+	TxConfirmPollInterval = time.Second
+
+	// ErrTxNotCommitted is returned for a transaction that was broadcast but not committed in
+	// a block before its confirm timeout expired
+	ErrTxNotCommitted = errors.New("transaction not committed")
+
 	RtyAttNum = uint(5)
 	RtyAtt    = retry.Attempts(RtyAttNum)
 	RtyDel    = retry.Delay(time.Millisecond * 400)
@@ -59,6 +71,41 @@ type Chain struct {
 	GasPrices      string  `yaml:"gas-prices" json:"gas-prices"`
 	TrustingPeriod string  `yaml:"trusting-period" json:"trusting-period"`
 
+	// RPCAddrs are additional RPC endpoints of the chain. Requests are routed to the healthiest
+	// of them and RPCAddr.
+	RPCAddrs []string `yaml:"rpc-addrs,omitempty" json:"rpc-addrs,omitempty"`
+
+	// Keys are additional keys relay transactions are signed with, round-robin with Key
+	Keys []string `yaml:"keys,omitempty" json:"keys,omitempty"`
+
+	// FeeGranter is the address of an account that pays the fees of every transaction
+	// through a x/feegrant allowance, so the signing keys don't need to hold funds
+	FeeGranter string `yaml:"fee-granter,omitempty" json:"fee-granter,omitempty"`
+
+	// AuthzGranter is the address of an account the chain's relay and client update msgs are sent
+	// on behalf of. The msgs are wrapped in an authz MsgExec sent by the signing key.
+	AuthzGranter string `yaml:"authz-granter,omitempty" json:"authz-granter,omitempty"`
+
+	// ConfirmTimeout, if set, makes transactions broadcast in sync mode and confirmed by
+	// polling for their inclusion in a block until the timeout expires
+	ConfirmTimeout string `yaml:"confirm-timeout,omitempty" json:"confirm-timeout,omitempty"`
+
+	// Signer is the http(s) URL of a signer daemon holding the chain's keys. Transactions are
+	// signed with the local keyring if unset.
+	Signer string `yaml:"signer,omitempty" json:"signer,omitempty"`
+
+	// SignerTokenFile is the file holding the token the signer daemon authenticates requests with
+	SignerTokenFile string `yaml:"signer-token-file,omitempty" json:"signer-token-file,omitempty"`
+
+	// GRPCAddr is the gRPC endpoint of the chain. Queries that don't need proofs go to it
+	// instead of the RPC endpoints if set.
+	GRPCAddr string `yaml:"grpc-addr,omitempty" json:"grpc-addr,omitempty"`
+
+	// VerifyQueries makes the relayer check the proofs of store queries against the app hashes
+	// of the chain's light blocks, verified from its light client database, and fail on any
+	// mismatch. The database must be initialized with rly light init.
+	VerifyQueries bool `yaml:"verify-queries,omitempty" json:"verify-queries,omitempty"`
+
 	// TODO: make these private
 	HomePath string                `yaml:"-" json:"-"`
 	PathEnd  *PathEnd              `yaml:"-" json:"-"`
@@ -68,14 +115,37 @@ type Chain struct {
 	Provider provtypes.Provider    `yaml:"-" json:"-"`
 
 	address sdk.AccAddress
+	signer  Signer
 	logger  log.Logger
 	timeout time.Duration
 	debug   bool
+	dryRun  bool
+
+	// connection to GRPCAddr, shared by copies of the chain
+	grpcConn *grpc.ClientConn
+
+	// directory unsigned transactions are written to instead of being broadcast
+	generateDir string
+
+	// set to 1 while the relayer holds live event subscriptions to the chain
+	subscribed int32
+
+	// signing keys of the chain and their account sequences, shared by copies of the chain
+	pool *keyPool
 
 	// stores faucet addresses that have been used reciently
 	faucetAddrs map[string]time.Time
 }
 
+// Copy returns a shallow copy of the chain that shares its clients and keybase, so that the
+// same chain can be set to several paths at once
+func (c *Chain) Copy() *Chain {
+	out := *c
+	out.PathEnd = nil
+	atomic.StoreInt32(&out.subscribed, 0)
+	return &out
+}
+
 // ValidatePaths takes two chains and validates their paths
 func ValidatePaths(src, dst *Chain) error {
 	if err := src.PathEnd.ValidateFull(); err != nil {
@@ -206,17 +276,17 @@ func (c *Chain) Init(homePath string, timeout time.Duration, logger log.Logger,
 		return err
 	}
 
-	client, err := newRPCClient(c.RPCAddr, timeout)
-	if err != nil {
-		return err
+	if logger == nil {
+		logger = defaultChainLogger()
 	}
+	c.logger = logger
 
-	liteprovider, err := prov.New(c.ChainID, c.RPCAddr)
+	client, err := c.newFailoverClient(timeout)
 	if err != nil {
 		return err
 	}
 
-	_, err = time.ParseDuration(c.TrustingPeriod)
+	trustingPeriod, err := time.ParseDuration(c.TrustingPeriod)
 	if err != nil {
 		return fmt.Errorf("failed to parse trusting period (%s) for chain %s", c.TrustingPeriod, c.ChainID)
 	}
@@ -226,20 +296,46 @@ func (c *Chain) Init(homePath string, timeout time.Duration, logger log.Logger,
 		return fmt.Errorf("failed to parse gas prices (%s) for chain %s", c.GasPrices, c.ChainID)
 	}
 
+	if _, err = c.GetFeeGranter(); err != nil {
+		return err
+	}
+
+	if _, err = c.GetAuthzGranter(); err != nil {
+		return err
+	}
+
+	if c.ConfirmTimeout != "" {
+		if _, err = time.ParseDuration(c.ConfirmTimeout); err != nil {
+			return fmt.Errorf("failed to parse confirm timeout (%s) for chain %s", c.ConfirmTimeout, c.ChainID)
+		}
+	}
+
 	encodingConfig := c.MakeEncodingConfig()
 
 	c.Keybase = keybase
 	c.Client = client
 	c.HomePath = homePath
 	c.Encoding = encodingConfig
-	c.logger = logger
+	if c.signer, err = c.newSigner(keybase); err != nil {
+		return err
+	}
 	c.timeout = timeout
 	c.debug = debug
-	c.Provider = liteprovider
+	// light blocks are fetched from the healthiest endpoint too, and verified against the
+	// chain's light client database once it is initialized
+	lightLogger := log.NewNopLogger()
+	if debug {
+		lightLogger = logger.With("module", "light")
+	}
+	c.Provider = newLightProvider(c.ChainID, lightDir(homePath), trustingPeriod,
+		prov.NewWithClient(c.ChainID, client), lightLogger, c.logger)
 	c.faucetAddrs = make(map[string]time.Time)
+	c.pool = newKeyPool()
 
-	if c.logger == nil {
-		c.logger = defaultChainLogger()
+	if c.GRPCAddr != "" {
+		if c.grpcConn, err = dialGRPC(c.GRPCAddr); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -264,6 +360,13 @@ func (c *Chain) GetSelfVersion() uint64 {
 	return clienttypes.ParseChainID(c.ChainID)
 }
 
+// GetConfirmTimeout returns how long to wait for a broadcast transaction to be included in a
+// block, zero if transactions are broadcast in commit mode instead
+func (c *Chain) GetConfirmTimeout() time.Duration {
+	ct, _ := time.ParseDuration(c.ConfirmTimeout)
+	return ct
+}
+
 // GetTrustingPeriod returns the trusting period for the chain
 func (c *Chain) GetTrustingPeriod() time.Duration {
 	tp, _ := time.ParseDuration(c.TrustingPeriod)
@@ -286,40 +389,96 @@ func newRPCClient(addr string, timeout time.Duration) (*rpchttp.HTTP, error) {
 }
 
 // SendMsg wraps the msg in a stdtx, signs and sends it
-func (c *Chain) SendMsg(datagram sdk.Msg) (*sdk.TxResponse, bool, error) {
-	return c.SendMsgs([]sdk.Msg{datagram})
+func (c *Chain) SendMsg(ctx context.Context, datagram sdk.Msg) (*sdk.TxResponse, bool, error) {
+	return c.SendMsgs(ctx, []sdk.Msg{datagram})
 }
 
 // SendMsgs wraps the msgs in a StdTx, signs and sends it. An error is returned if there
 // was an issue sending the transaction. A successfully sent, but failed transaction will
 // not return an error. If a transaction is successfully sent, the result of the execution
 // of that transaction will be logged. A boolean indicating if a transaction was successfully
-// sent and executed successfully is returned.
-func (c *Chain) SendMsgs(msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
-	// Instantiate the client context
-	ctx := c.CLIContext(0)
+// sent and executed successfully is returned. If ctx is done before the transaction is
+// broadcasted, nothing is sent and the context's error is returned.
+// Transactions are signed with the locally tracked account sequence. If the chain has a
+// confirm timeout they are broadcast in sync mode, so concurrent calls can have several
+// transactions in the mempool at once; otherwise each one is committed before the next is
+// signed. In dry run mode the transaction is only simulated and in generate only mode it is
+// written unsigned to a file.
+func (c *Chain) SendMsgs(ctx context.Context, msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
 
-	// Query account details
-	txf, err := prepareFactory(ctx, c.TxFactory(0))
+	if c.dryRun {
+		return c.simulateMsgs(msgs)
+	}
+	if c.generateDir != "" {
+		return c.generateMsgs(msgs)
+	}
+
+	// sign and broadcast the transaction, resyncing the account sequence from chain and
+	// trying once more if another transaction got to the cached sequence first
+	res, err := c.broadcastMsgs(ctx, msgs)
+	if isSequenceMismatch(res, err) {
+		res, err = c.broadcastMsgs(ctx, msgs)
+	}
 	if err != nil {
 		return nil, false, err
 	}
 
-	// TODO: Make this work with new CalculateGas method
-	// https://github.com/cosmos/cosmos-sdk/blob/5725659684fc93790a63981c653feee33ecf3225/client/tx/tx.go#L297
-	// If users pass gas adjustment, then calculate gas
-	_, adjusted, err := CalculateGas(ctx.QueryWithData, txf, msgs...)
+	// with a confirm timeout, wait for the transaction to be committed, the response then
+	// carries its DeliverTx code
+	if timeout := c.GetConfirmTimeout(); timeout > 0 {
+		// the transaction failed CheckTx and never entered the mempool
+		if res.Code != 0 {
+			c.LogFailedTx(res, nil, msgs)
+			return res, false, nil
+		}
+
+		if res, err = c.confirmTx(ctx, res.TxHash, timeout); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// transaction was executed, log the success or failure using the tx response code
+	// NOTE: error is nil, logic should use the returned error to determine if the
+	// transaction was successfully executed.
+	if res.Code != 0 {
+		c.LogFailedTx(res, err, msgs)
+		return res, false, nil
+	}
+
+	c.LogSuccessTx(res, msgs)
+	return res, true, nil
+}
+
+// broadcastMsgs signs the msgs with the next account sequence of the chain's key and broadcasts
+// the transaction. With a confirm timeout it is broadcast in sync mode, returning once it passed
+// or failed CheckTx, otherwise in commit mode. Transactions are signed and broadcast one at a
+// time so they enter the mempool in sequence order.
+func (c *Chain) broadcastMsgs(ctx context.Context, msgs []sdk.Msg) (*sdk.TxResponse, error) {
+	cliCtx := c.CLIContext(0)
+
+	// msgs sent on behalf of the authz granter are executed through its grants
+	msgs, err := c.wrapAuthz(msgs)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
-	// Set the gas amount on the transaction factory
-	txf = txf.WithGas(adjusted)
+	seq := c.accountSequence()
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
 
-	// Build the transaction builder
-	txb, err := tx.BuildUnsignedTx(txf, msgs...)
+	// Query account details if they aren't cached yet
+	txf, err := seq.factory(cliCtx, c.TxFactory(0))
 	if err != nil {
-		return nil, false, err
+		return nil, err
+	}
+
+	txf, txb, err := c.buildUnsignedTx(cliCtx, txf, msgs)
+	if err != nil {
+		seq.invalidateOnMismatch(err)
+		return nil, err
 	}
 
 	// Attach the signature to the transaction
@@ -328,59 +487,130 @@ func (c *Chain) SendMsgs(msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
 	for _, msg := range msgs {
 		c.Encoding.Marshaler.MustMarshalJSON(msg)
 	}
-	err = tx.Sign(txf, c.Key, txb, false)
+	err = c.signTx(txf, txb)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	// Generate the transaction bytes
-	txBytes, err := ctx.TxConfig.TxEncoder()(txb.GetTx())
+	txBytes, err := cliCtx.TxConfig.TxEncoder()(txb.GetTx())
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	// Broadcast those bytes
-	res, err := ctx.BroadcastTx(txBytes)
+	if c.GetConfirmTimeout() == 0 {
+		return c.broadcastTxCommit(ctx, seq, txBytes)
+	}
+	res, err := c.Client.BroadcastTxSync(ctx, txBytes)
 	if err != nil {
-		return nil, false, err
+		// it isn't known whether the transaction made it into the mempool
+		seq.invalidate()
+		if errRes := sdkCtx.CheckTendermintError(err, txBytes); errRes != nil {
+			return errRes, nil
+		}
+		return nil, err
 	}
 
-	// transaction was executed, log the success or failure using the tx response code
-	// NOTE: error is nil, logic should use the returned error to determine if the
-	// transaction was successfully executed.
+	out := sdk.NewResponseFormatBroadcastTx(res)
 	if res.Code != 0 {
-		c.LogFailedTx(res, err, msgs)
-		return res, false, nil
+		seq.invalidateOnMismatch(errorFromTxResponse(out))
+		return out, nil
 	}
 
-	c.LogSuccessTx(res, msgs)
-	return res, true, nil
+	// the transaction is in the mempool and will use up the sequence
+	seq.increment()
+	return out, nil
 }
 
-func prepareFactory(clientCtx sdkCtx.Context, txf tx.Factory) (tx.Factory, error) {
-	from := clientCtx.GetFromAddress()
+// broadcastTxCommit broadcasts the transaction bytes signed with the sequence seq is locked for and
+// waits until the transaction is committed in a block or ctx is done. Mempool errors are returned
+// as a failed tx response.
+func (c *Chain) broadcastTxCommit(ctx context.Context, seq *accountSequence, txBytes []byte) (*sdk.TxResponse, error) {
+	res, err := c.Client.BroadcastTxCommit(ctx, txBytes)
+	if err != nil {
+		// the transaction may still be in the mempool
+		seq.invalidate()
+		if errRes := sdkCtx.CheckTendermintError(err, txBytes); errRes != nil {
+			return errRes, nil
+		}
+		return nil, err
+	}
 
-	if err := txf.AccountRetriever().EnsureExists(clientCtx, from); err != nil {
-		return txf, err
+	out := sdk.NewResponseFormatBroadcastTxCommit(res)
+	if res.CheckTx.Code != 0 {
+		seq.invalidateOnMismatch(errorFromTxResponse(out))
+		return out, nil
 	}
 
-	initNum, initSeq := txf.AccountNumber(), txf.Sequence()
-	if initNum == 0 || initSeq == 0 {
-		num, seq, err := txf.AccountRetriever().GetAccountNumberSequence(clientCtx, from)
-		if err != nil {
-			return txf, err
-		}
+	// the committed transaction used up the sequence, whether DeliverTx failed or not
+	seq.increment()
+	return out, nil
+}
+
+// confirmTx polls for the transaction with the given hash until it is committed in a block or
+// the timeout expires. A transaction that is never committed, e.g. because it was dropped from
+// the mempool, returns ErrTxNotCommitted.
+func (c *Chain) confirmTx(ctx context.Context, txHash string, timeout time.Duration) (*sdk.TxResponse, error) {
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	expired := time.NewTimer(timeout)
+	defer expired.Stop()
+
+	ticker := time.NewTicker(TxConfirmPollInterval)
+	defer ticker.Stop()
 
-		if initNum == 0 {
-			txf = txf.WithAccountNumber(num)
+	for {
+		// the tx isn't found until it is committed
+		if res, err := c.Client.Tx(ctx, hash, false); err == nil {
+			return sdk.NewResponseResultTx(res, nil, ""), nil
 		}
 
-		if initSeq == 0 {
-			txf = txf.WithSequence(seq)
+		select {
+		case <-ticker.C:
+		case <-expired.C:
+			// a dropped transaction never used up its sequence
+			c.accountSequence().resync()
+			return nil, fmt.Errorf("tx %X on chain %s not committed within %s: %w",
+				hash, c.ChainID, timeout, ErrTxNotCommitted)
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
+}
+
+// buildUnsignedTx simulates the msgs to set the gas and fees of txf and builds the unsigned
+// transaction, with the fees paid by the fee granter if the chain has one
+func (c *Chain) buildUnsignedTx(cliCtx sdkCtx.Context, txf tx.Factory, msgs []sdk.Msg) (tx.Factory, sdkCtx.TxBuilder, error) {
+	// TODO: Make this work with new CalculateGas method
+	// https://github.com/cosmos/cosmos-sdk/blob/5725659684fc93790a63981c653feee33ecf3225/client/tx/tx.go#L297
+	// If users pass gas adjustment, then calculate gas
+	_, adjusted, err := CalculateGas(cliCtx.QueryWithData, txf, msgs...)
+	if err != nil {
+		return txf, nil, err
+	}
+
+	// Set the gas amount on the transaction factory
+	txf = txf.WithGas(adjusted)
+
+	// Build the transaction builder
+	txb, err := tx.BuildUnsignedTx(txf, msgs...)
+	if err != nil {
+		return txf, nil, err
+	}
 
-	return txf, nil
+	// Have the fee granter pay the fees
+	granter, err := c.GetFeeGranter()
+	if err != nil {
+		return txf, nil, err
+	}
+	if granter != nil {
+		txb.SetFeeGranter(granter)
+	}
+	return txf, txb, nil
 }
 
 // protoTxProvider is a type which can provide a proto transaction. It is a
@@ -526,12 +756,7 @@ func (c *Chain) GetAddress() (sdk.AccAddress, error) {
 	}
 
 	// Signing key for c chain
-	srcAddr, err := c.Keybase.Key(c.Key)
-	if err != nil {
-		return nil, err
-	}
-
-	return srcAddr.GetAddress(), nil
+	return c.KeyAddress(c.Key)
 }
 
 // MustGetAddress used for brevity
@@ -577,6 +802,13 @@ func (c *Chain) Update(key, value string) (out *Chain, err error) {
 	switch key {
 	case "key":
 		out.Key = value
+	case "keys":
+		out.Keys = nil
+		for _, k := range strings.Split(value, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				out.Keys = append(out.Keys, k)
+			}
+		}
 	case "chain-id":
 		out.ChainID = value
 	case "rpc-addr":
@@ -584,6 +816,17 @@ func (c *Chain) Update(key, value string) (out *Chain, err error) {
 			return
 		}
 		out.RPCAddr = value
+	case "rpc-addrs":
+		out.RPCAddrs = nil
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr == "" {
+				continue
+			}
+			if _, err = rpchttp.New(addr, "/websocket"); err != nil {
+				return
+			}
+			out.RPCAddrs = append(out.RPCAddrs, addr)
+		}
 	case "gas-adjustment":
 		adj, err := strconv.ParseFloat(value, 64)
 		if err != nil {
@@ -603,6 +846,47 @@ func (c *Chain) Update(key, value string) (out *Chain, err error) {
 			return
 		}
 		out.TrustingPeriod = value
+	case "authz-granter":
+		if value != "" {
+			if _, err = sdk.GetFromBech32(value, out.AccountPrefix); err != nil {
+				return
+			}
+		}
+		out.AuthzGranter = value
+	case "fee-granter":
+		if value != "" {
+			if _, err = sdk.GetFromBech32(value, out.AccountPrefix); err != nil {
+				return
+			}
+		}
+		out.FeeGranter = value
+	case "confirm-timeout":
+		if value != "" {
+			if _, err = time.ParseDuration(value); err != nil {
+				return
+			}
+		}
+		out.ConfirmTimeout = value
+	case "signer":
+		if value != "" {
+			if _, err = NewRemoteSigner(value, out.ChainID, "", nil); err != nil {
+				return
+			}
+		}
+		out.Signer = value
+	case "signer-token-file":
+		if value != "" {
+			if _, err = ReadSignerToken(value); err != nil {
+				return
+			}
+		}
+		out.SignerTokenFile = value
+	case "grpc-addr":
+		out.GRPCAddr = value
+	case "verify-queries":
+		if out.VerifyQueries, err = strconv.ParseBool(value); err != nil {
+			return
+		}
 	default:
 		return out, fmt.Errorf("key %s not found", key)
 	}
@@ -648,7 +932,7 @@ func (c *Chain) SendAndPrint(txs []sdk.Msg, text, indent bool) (err error) {
 		}
 	}
 	// SendAndPrint sends the transaction with printing options from the CLI
-	res, _, err := c.SendMsgs(txs)
+	res, _, err := c.SendMsgs(context.Background(), txs)
 	if err != nil {
 		return err
 	}
@@ -694,6 +978,20 @@ func (c Chains) Gets(chainIDs ...string) (map[string]*Chain, error) {
 	return out, nil
 }
 
+// Close closes the light client databases of the chains
+func (c Chains) Close() error {
+	var errs []string
+	for _, chain := range c {
+		if err := chain.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", chain.ChainID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close chains: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 // GetRPCPort returns the port configured for the chain
 func (c *Chain) GetRPCPort() string {
 	u, _ := url.Parse(c.RPCAddr)
@@ -822,7 +1120,7 @@ func (c *Chain) UpgradeChain(dst *Chain, plan *upgradetypes.Plan, deposit sdk.Co
 		return err
 	}
 
-	_, _, err = c.SendMsg(msg)
+	_, _, err = c.SendMsg(context.Background(), msg)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package relayer
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,6 +12,10 @@ import (
 func (c *Chain) CreateOpenChannels(dst *Chain, maxRetries uint64, to time.Duration) (modified bool, err error) {
 	// client and connection identifiers must be filled in
 	if err := ValidateConnectionPaths(c, dst); err != nil {
+		if !c.broadcasts() {
+			c.Log(fmt.Sprintf("- not broadcasting, skipping channel handshake: %v", err))
+			return modified, nil
+		}
 		return modified, err
 	}
 	// ports must be valid and channel ORDER must be the same
@@ -25,10 +30,19 @@ func (c *Chain) CreateOpenChannels(dst *Chain, maxRetries uint64, to time.Durati
 		if err != nil {
 			c.Log(err.Error())
 		}
-		if recentlyModified {
+		// identifiers found on chain are written to the config, unless this is a dry run
+		if recentlyModified && !c.dryRun {
 			modified = true
 		}
 
+		// transactions that aren't broadcast don't change any state, so the handshake can't go
+		// past its next step. Taking an identifier that exists on chain doesn't need a
+		// transaction, so the handshake moves on to the step after it.
+		if !c.broadcasts() && !(success && recentlyModified) {
+			c.logNextStep("channel")
+			return modified, nil
+		}
+
 		switch {
 		// In the case of success and this being the last transaction
 		// debug logging, log created channel and break
@@ -125,7 +139,7 @@ func ExecuteChannelStep(src, dst *Chain) (success, last, modified bool, err erro
 			return false, false, false, err
 		}
 
-		_, success, err = src.SendMsgs(msgs)
+		_, success, err = src.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -154,7 +168,7 @@ func ExecuteChannelStep(src, dst *Chain) (success, last, modified bool, err erro
 			return false, false, false, err
 		}
 
-		_, success, err = src.SendMsgs(msgs)
+		_, success, err = src.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -182,7 +196,7 @@ func ExecuteChannelStep(src, dst *Chain) (success, last, modified bool, err erro
 			return false, false, false, err
 		}
 
-		_, success, err = dst.SendMsgs(msgs)
+		_, success, err = dst.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -210,7 +224,7 @@ func ExecuteChannelStep(src, dst *Chain) (success, last, modified bool, err erro
 
 		last = true
 
-		_, success, err = src.SendMsgs(msgs)
+		_, success, err = src.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -236,7 +250,7 @@ func ExecuteChannelStep(src, dst *Chain) (success, last, modified bool, err erro
 			return false, false, false, err
 		}
 
-		_, success, err = dst.SendMsgs(msgs)
+		_, success, err = dst.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -283,11 +297,17 @@ func InitializeChannel(src, dst *Chain) (success, modified bool, err error) {
 				return false, false, err
 			}
 
-			res, success, err := src.SendMsgs(msgs)
+			res, success, err := src.SendMsgs(context.Background(), msgs)
 			if !success {
 				return false, false, err
 			}
 
+			// a transaction that isn't broadcast doesn't open the channel, so there is no
+			// identifier to take and nothing to record
+			if !src.broadcasts() {
+				return true, false, nil
+			}
+
 			// update channel identifier in PathEnd
 			// use index 1, channel open init is the second message in the transaction
 			channelID, err = ParseChannelIDFromEvents(res.Logs[1].Events)
@@ -327,11 +347,17 @@ func InitializeChannel(src, dst *Chain) (success, modified bool, err error) {
 				return false, false, err
 			}
 
-			res, success, err := src.SendMsgs(msgs)
+			res, success, err := src.SendMsgs(context.Background(), msgs)
 			if !success {
 				return false, false, err
 			}
 
+			// a transaction that isn't broadcast doesn't open the channel, so there is no
+			// identifier to take and nothing to record
+			if !src.broadcasts() {
+				return true, false, nil
+			}
+
 			// update channel identifier in PathEnd
 			// use index 1, channel open try is the second message in the transaction
 			channelID, err = ParseChannelIDFromEvents(res.Logs[1].Events)
@@ -372,11 +398,17 @@ func InitializeChannel(src, dst *Chain) (success, modified bool, err error) {
 				return false, false, err
 			}
 
-			res, success, err := dst.SendMsgs(msgs)
+			res, success, err := dst.SendMsgs(context.Background(), msgs)
 			if !success {
 				return false, false, err
 			}
 
+			// a transaction that isn't broadcast doesn't open the channel, so there is no
+			// identifier to take and nothing to record
+			if !dst.broadcasts() {
+				return true, false, nil
+			}
+
 			// update channel identifier in PathEnd
 			// use index 1, channel open try is the second message in the transaction
 			channelID, err = ParseChannelIDFromEvents(res.Logs[1].Events)
@@ -396,9 +428,10 @@ func InitializeChannel(src, dst *Chain) (success, modified bool, err error) {
 	}
 }
 
-// CloseChannel runs the channel closing messages on timeout until they pass
+// CloseChannel runs the channel closing messages on timeout until they pass, then times out the
+// packets still in flight with the given strategy
 // TODO: add max retries or something to this function
-func (c *Chain) CloseChannel(dst *Chain, to time.Duration) error {
+func (c *Chain) CloseChannel(dst *Chain, to time.Duration, strategy Strategy) error {
 
 	ticker := time.NewTicker(to)
 	for ; true; <-ticker.C {
@@ -411,7 +444,15 @@ func (c *Chain) CloseChannel(dst *Chain, to time.Duration) error {
 			break
 		}
 
-		if closeSteps.Send(c, dst); closeSteps.Success() && closeSteps.Last {
+		closeSteps.Send(context.Background(), c, dst)
+
+		// transactions that aren't broadcast don't change any state, so the handshake can't go
+		// past its next step
+		if !c.broadcasts() {
+			return nil
+		}
+
+		if closeSteps.Success() && closeSteps.Last {
 			srcChan, dstChan, err := QueryChannelPair(c, dst, 0, 0)
 			if err != nil {
 				return err
@@ -425,7 +466,46 @@ func (c *Chain) CloseChannel(dst *Chain, to time.Duration) error {
 			break
 		}
 	}
-	return nil
+
+	// refund any packets that were still in flight when the channel was closed
+	srcChan, dstChan, err := QueryChannelPair(c, dst, 0, 0)
+	if err != nil {
+		return err
+	}
+	if srcChan.Channel.State != chantypes.CLOSED || dstChan.Channel.State != chantypes.CLOSED {
+		return nil
+	}
+	return c.timeoutPacketsOnClose(dst, strategy)
+}
+
+// TimeoutPacketsOnClose relays a MsgTimeoutOnClose with the given strategy for every packet sent
+// over a closed channel that was not received before the channel was closed, in both directions
+func (c *Chain) TimeoutPacketsOnClose(dst *Chain, strategy Strategy) error {
+	srcChan, dstChan, err := QueryChannelPair(c, dst, 0, 0)
+	if err != nil {
+		return err
+	}
+	if srcChan.Channel.State != chantypes.CLOSED || dstChan.Channel.State != chantypes.CLOSED {
+		return fmt.Errorf("channel between [%s]chan{%s} and [%s]chan{%s} is not closed",
+			c.ChainID, c.PathEnd.ChannelID, dst.ChainID, dst.PathEnd.ChannelID)
+	}
+	return c.timeoutPacketsOnClose(dst, strategy)
+}
+
+// timeoutPacketsOnClose relays the packets in flight on a channel whose ends are both closed
+func (c *Chain) timeoutPacketsOnClose(dst *Chain, strategy Strategy) error {
+	// RelayPackets times out the packets on close since both channel ends are closed
+	sp, err := strategy.UnrelayedSequences(context.Background(), c, dst)
+	if err != nil {
+		return err
+	}
+	if len(sp.Src) == 0 && len(sp.Dst) == 0 {
+		return nil
+	}
+
+	c.Log(fmt.Sprintf("- timing out %d packets in flight on closed channel between [%s]chan{%s} and [%s]chan{%s}",
+		len(sp.Src)+len(sp.Dst), c.ChainID, c.PathEnd.ChannelID, dst.ChainID, dst.PathEnd.ChannelID))
+	return strategy.RelayPackets(context.Background(), c, dst, sp)
 }
 
 // CloseChannelStep returns the next set of messages for closing a channel with given
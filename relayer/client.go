@@ -1,6 +1,7 @@
 package relayer
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"time"
@@ -82,7 +83,7 @@ func (c *Chain) CreateClients(dst *Chain, allowUpdateAfterExpiry, allowUpdateAft
 			msgs := []sdk.Msg{createMsg}
 
 			// if a matching client does not exist, create one
-			res, success, err := c.SendMsgs(msgs)
+			res, success, err := c.SendMsgs(context.Background(), msgs)
 			if err != nil {
 				c.LogFailedTx(res, err, msgs)
 				return modified, err
@@ -92,18 +93,24 @@ func (c *Chain) CreateClients(dst *Chain, allowUpdateAfterExpiry, allowUpdateAft
 				return modified, fmt.Errorf("tx failed: %s", res.RawLog)
 			}
 
-			// update the client identifier
-			// use index 0, the transaction only has one message
-			clientID, err = ParseClientIDFromEvents(res.Logs[0].Events)
-			if err != nil {
-				return modified, err
+			// a transaction that isn't broadcast doesn't create the client
+			if c.broadcasts() {
+				// update the client identifier
+				// use index 0, the transaction only has one message
+				clientID, err = ParseClientIDFromEvents(res.Logs[0].Events)
+				if err != nil {
+					return modified, err
+				}
 			}
 		} else if c.debug {
 			c.logIdentifierExists(dst, "client", clientID)
 		}
 
-		c.PathEnd.ClientID = clientID
-		modified = true
+		// a client found on chain is recorded, unless this is a dry run
+		if clientID != "" {
+			c.PathEnd.ClientID = clientID
+			modified = !c.dryRun
+		}
 
 	} else {
 		// Ensure client exists in the event of user inputted identifiers
@@ -158,7 +165,7 @@ func (c *Chain) CreateClients(dst *Chain, allowUpdateAfterExpiry, allowUpdateAft
 			msgs := []sdk.Msg{createMsg}
 
 			// if a matching client does not exist, create one
-			res, success, err := dst.SendMsgs(msgs)
+			res, success, err := dst.SendMsgs(context.Background(), msgs)
 			if err != nil {
 				dst.LogFailedTx(res, err, msgs)
 				return modified, err
@@ -168,17 +175,22 @@ func (c *Chain) CreateClients(dst *Chain, allowUpdateAfterExpiry, allowUpdateAft
 				return modified, fmt.Errorf("tx failed: %s", res.RawLog)
 			}
 
-			// update client identifier
-			clientID, err = ParseClientIDFromEvents(res.Logs[0].Events)
-			if err != nil {
-				return modified, err
+			// a transaction that isn't broadcast doesn't create the client
+			if dst.broadcasts() {
+				// update client identifier
+				clientID, err = ParseClientIDFromEvents(res.Logs[0].Events)
+				if err != nil {
+					return modified, err
+				}
 			}
 		} else if c.debug {
 			c.logIdentifierExists(dst, "client", clientID)
 		}
 
-		dst.PathEnd.ClientID = clientID
-		modified = true
+		if clientID != "" {
+			dst.PathEnd.ClientID = clientID
+			modified = modified || !dst.dryRun
+		}
 
 	} else {
 		// Ensure client exists in the event of user inputted identifiers
@@ -191,6 +203,13 @@ func (c *Chain) CreateClients(dst *Chain, allowUpdateAfterExpiry, allowUpdateAft
 
 	}
 
+	if c.PathEnd.ClientID == "" || dst.PathEnd.ClientID == "" {
+		c.Log(fmt.Sprintf("- not broadcasting, clients weren't created on chain[%s] and chain[%s]",
+			c.ChainID, dst.ChainID))
+		c.logNextStep("client")
+		return modified, nil
+	}
+
 	c.Log(fmt.Sprintf("★ Clients created: client(%s) on chain[%s] and client(%s) on chain[%s]",
 		c.PathEnd.ClientID, c.ChainID, dst.PathEnd.ClientID, dst.ChainID))
 
@@ -236,7 +255,7 @@ func (c *Chain) UpdateClients(dst *Chain) (err error) {
 
 	// Send msgs to both chains
 	if clients.Ready() {
-		if clients.Send(c, dst); clients.Success() {
+		if clients.Send(context.Background(), c, dst); clients.Success() {
 			c.Log(fmt.Sprintf("★ Clients updated: [%s]client(%s) {%d}->{%d} and [%s]client(%s) {%d}->{%d}",
 				c.ChainID,
 				c.PathEnd.ClientID,
@@ -294,7 +313,7 @@ func (c *Chain) UpgradeClients(dst *Chain, height int64) error {
 		upgradeMsg,
 	}
 
-	_, _, err = c.SendMsgs(msgs)
+	_, _, err = c.SendMsgs(context.Background(), msgs)
 	if err != nil {
 		return err
 	}
@@ -402,7 +421,7 @@ func IsMatchingConsensusState(consensusStateA, consensusStateB *ibctmtypes.Conse
 }
 
 // AutoUpdateClient update client automatically to prevent expiry
-func AutoUpdateClient(src, dst *Chain, thresholdTime time.Duration) (time.Duration, error) {
+func AutoUpdateClient(ctx context.Context, src, dst *Chain, thresholdTime time.Duration) (time.Duration, error) {
 	srch, dsth, err := QueryLatestHeights(src, dst)
 	if err != nil {
 		return 0, err
@@ -465,7 +484,7 @@ func AutoUpdateClient(src, dst *Chain, thresholdTime time.Duration) (time.Durati
 
 	msgs := []sdk.Msg{updateMsg}
 
-	res, success, err := src.SendMsgs(msgs)
+	res, success, err := src.SendMsgs(ctx, msgs)
 	if err != nil {
 		return 0, err
 	}
@@ -1,6 +1,7 @@
 package relayer
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,6 +13,10 @@ import (
 func (c *Chain) CreateOpenConnections(dst *Chain, maxRetries uint64, to time.Duration) (modified bool, err error) {
 	// client identifiers must be filled in
 	if err := ValidateClientPaths(c, dst); err != nil {
+		if !c.broadcasts() {
+			c.Log(fmt.Sprintf("- not broadcasting, skipping connection handshake: %v", err))
+			return modified, nil
+		}
 		return modified, err
 	}
 
@@ -23,10 +28,19 @@ func (c *Chain) CreateOpenConnections(dst *Chain, maxRetries uint64, to time.Dur
 			c.Log(fmt.Sprintf("%v", err))
 		}
 
-		if recentlyModified {
+		// identifiers found on chain are written to the config, unless this is a dry run
+		if recentlyModified && !c.dryRun {
 			modified = true
 		}
 
+		// transactions that aren't broadcast don't change any state, so the handshake can't go
+		// past its next step. Taking an identifier that exists on chain doesn't need a
+		// transaction, so the handshake moves on to the step after it.
+		if !c.broadcasts() && !(success && recentlyModified) {
+			c.logNextStep("connection")
+			return modified, nil
+		}
+
 		switch {
 		// In the case of success and this being the last transaction
 		// debug logging, log created connection and break
@@ -124,7 +138,7 @@ func ExecuteConnectionStep(src, dst *Chain) (success, last, modified bool, err e
 			return false, false, false, err
 		}
 
-		_, success, err = src.SendMsgs(msgs)
+		_, success, err = src.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -143,7 +157,7 @@ func ExecuteConnectionStep(src, dst *Chain) (success, last, modified bool, err e
 			return false, false, false, err
 		}
 
-		_, success, err = src.SendMsgs(msgs)
+		_, success, err = src.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -161,7 +175,7 @@ func ExecuteConnectionStep(src, dst *Chain) (success, last, modified bool, err e
 			return false, false, false, err
 		}
 
-		_, success, err = dst.SendMsgs(msgs)
+		_, success, err = dst.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -177,7 +191,7 @@ func ExecuteConnectionStep(src, dst *Chain) (success, last, modified bool, err e
 			return false, false, false, err
 		}
 
-		_, success, err = src.SendMsgs(msgs)
+		_, success, err = src.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -195,7 +209,7 @@ func ExecuteConnectionStep(src, dst *Chain) (success, last, modified bool, err e
 			return false, false, false, err
 		}
 
-		_, success, err = dst.SendMsgs(msgs)
+		_, success, err = dst.SendMsgs(context.Background(), msgs)
 		if !success {
 			return false, false, false, err
 		}
@@ -247,11 +261,17 @@ func InitializeConnection(src, dst *Chain) (success, modified bool, err error) {
 				return false, false, err
 			}
 
-			res, success, err := src.SendMsgs(msgs)
+			res, success, err := src.SendMsgs(context.Background(), msgs)
 			if !success {
 				return false, false, err
 			}
 
+			// a transaction that isn't broadcast doesn't open the connection, so there is no
+			// identifier to take and nothing to record
+			if !src.broadcasts() {
+				return true, false, nil
+			}
+
 			// update connection identifier in PathEnd
 			// use index 1, connection open init is the second message in the transaction
 			connectionID, err = ParseConnectionIDFromEvents(res.Logs[1].Events)
@@ -280,11 +300,17 @@ func InitializeConnection(src, dst *Chain) (success, modified bool, err error) {
 				return false, false, err
 			}
 
-			res, success, err := src.SendMsgs(msgs)
+			res, success, err := src.SendMsgs(context.Background(), msgs)
 			if !success {
 				return false, false, err
 			}
 
+			// a transaction that isn't broadcast doesn't open the connection, so there is no
+			// identifier to take and nothing to record
+			if !src.broadcasts() {
+				return true, false, nil
+			}
+
 			// update connection identifier in PathEnd
 			// use index 1, connection open try is the second message in the transaction
 			connectionID, err = ParseConnectionIDFromEvents(res.Logs[1].Events)
@@ -313,11 +339,17 @@ func InitializeConnection(src, dst *Chain) (success, modified bool, err error) {
 				return false, false, err
 			}
 
-			res, success, err := dst.SendMsgs(msgs)
+			res, success, err := dst.SendMsgs(context.Background(), msgs)
 			if !success {
 				return false, false, err
 			}
 
+			// a transaction that isn't broadcast doesn't open the connection, so there is no
+			// identifier to take and nothing to record
+			if !dst.broadcasts() {
+				return true, false, nil
+			}
+
 			// update connection identifier in PathEnd
 			// use index 1, connection open try is the second message in the transaction
 			connectionID, err = ParseConnectionIDFromEvents(res.Logs[1].Events)
@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SimulatedTx is a transaction that was simulated instead of broadcast in dry run mode
+type SimulatedTx struct {
+	ChainID string            `json:"chain-id" yaml:"chain-id"`
+	Signer  string            `json:"signer" yaml:"signer"`
+	Msgs    []json.RawMessage `json:"msgs" yaml:"msgs"`
+	Gas     uint64            `json:"gas,omitempty" yaml:"gas,omitempty"`
+	Fees    sdk.Coins         `json:"fees,omitempty" yaml:"fees,omitempty"`
+	Error   string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// SetDryRun sets whether the chain simulates transactions instead of broadcasting them
+func (c *Chain) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// DryRun returns true if the chain simulates transactions instead of broadcasting them
+func (c *Chain) DryRun() bool {
+	return c.dryRun
+}
+
+// simulateMsgs simulates the transaction the msgs would be sent in and prints the msgs along
+// with the estimated gas and fees, or the reason the simulation failed. Nothing is broadcast.
+// The returned response carries the estimated gas and, if the simulation failed, its error.
+func (c *Chain) simulateMsgs(msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
+	sim := SimulatedTx{ChainID: c.ChainID, Signer: c.Key}
+	for _, msg := range msgs {
+		bz, err := c.Encoding.Marshaler.MarshalInterfaceJSON(msg)
+		if err != nil {
+			return nil, false, err
+		}
+		sim.Msgs = append(sim.Msgs, bz)
+	}
+
+	gas, fees, simErr := c.estimateFees(msgs)
+	if simErr != nil {
+		sim.Error = simErr.Error()
+	} else {
+		sim.Gas, sim.Fees = gas, fees
+	}
+
+	out, err := json.Marshal(sim)
+	if err != nil {
+		return nil, false, err
+	}
+	fmt.Println(string(out))
+
+	res := &sdk.TxResponse{GasWanted: int64(gas)}
+	if simErr != nil {
+		res.Code, res.RawLog = 1, simErr.Error()
+		return res, false, nil
+	}
+	return res, true, nil
+}
+
+// estimateFees simulates the transaction the msgs would be sent in and returns its estimated
+// gas and the fees the chain's gas prices charge for it
+func (c *Chain) estimateFees(msgs []sdk.Msg) (uint64, sdk.Coins, error) {
+	msgs, err := c.wrapAuthz(msgs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	cliCtx := c.CLIContext(0)
+
+	seq := c.accountSequence()
+	seq.mu.Lock()
+	txf, err := seq.factory(cliCtx, c.TxFactory(0))
+	seq.mu.Unlock()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	_, gas, err := CalculateGas(cliCtx.QueryWithData, txf, msgs...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// fees are charged the same way tx.BuildUnsignedTx charges them
+	gasPrices, err := sdk.ParseDecCoins(c.GasPrices)
+	if err != nil {
+		return 0, nil, err
+	}
+	fees := sdk.NewCoins()
+	for _, gp := range gasPrices {
+		fee := gp.Amount.Mul(sdk.NewDec(int64(gas)))
+		fees = fees.Add(sdk.NewCoin(gp.Denom, fee.Ceil().RoundInt()))
+	}
+	return gas, fees, nil
+}
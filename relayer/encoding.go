@@ -102,10 +102,10 @@ func (pc *ProtoCodec) MustMarshalLengthPrefixed(o codec.ProtoMarshaler) []byte {
 func (pc *ProtoCodec) Unmarshal(bz []byte, ptr codec.ProtoMarshaler) error {
 	done := pc.useContext()
 	err := ptr.Unmarshal(bz)
+	done()
 	if err != nil {
 		return err
 	}
-	done()
 	err = types.UnpackInterfaces(ptr, pc)
 	if err != nil {
 		return err
@@ -148,7 +148,7 @@ func (pc *ProtoCodec) MustUnmarshalLengthPrefixed(bz []byte, ptr codec.ProtoMars
 // MarshalJSON implements JSONCodec.MarshalJSON method,
 // it marshals to JSON using proto codec.
 func (pc *ProtoCodec) MarshalJSON(o proto.Message) ([]byte, error) {
-	done := pc.useContext()
+	defer pc.useContext()()
 	m, ok := o.(codec.ProtoMarshaler)
 	if !ok {
 		return nil, fmt.Errorf("cannot protobuf JSON encode unsupported type: %T", o)
@@ -157,7 +157,6 @@ func (pc *ProtoCodec) MarshalJSON(o proto.Message) ([]byte, error) {
 	if err != nil {
 		return []byte{}, err
 	}
-	done()
 	return bz, nil
 }
 
@@ -235,7 +234,7 @@ func (pc *ProtoCodec) UnmarshalInterface(bz []byte, ptr interface{}) error {
 // packs the provided value in an Any and then marshals it to bytes.
 // NOTE: to marshal a concrete type, you should use MarshalJSON instead
 func (pc *ProtoCodec) MarshalInterfaceJSON(x proto.Message) ([]byte, error) {
-	defer pc.useContext()()
+	// MarshalJSON uses the sdk context, it must not be held here
 	any, err := types.NewAnyWithValue(x)
 	if err != nil {
 		return nil, err
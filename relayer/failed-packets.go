@@ -0,0 +1,408 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+var (
+	// FailedPacketRetryInterval is how often the failed packet queue of a path is checked
+	// for packets that are due to be retried
+	FailedPacketRetryInterval = 30 * time.Second
+
+	// Bounds of the exponential backoff between retries of a failed packet
+	failedPacketMinBackoff = 30 * time.Second
+	failedPacketMaxBackoff = time.Hour
+)
+
+// Kinds of failed packets
+const (
+	FailedPacketKindPacket = "packet"
+	FailedPacketKindAck    = "ack"
+)
+
+// FailedPacket is a packet or acknowledgement that failed to relay
+type FailedPacket struct {
+	Path      string    `json:"path" yaml:"path"`
+	Kind      string    `json:"kind" yaml:"kind"`
+	Direction string    `json:"direction" yaml:"direction"`
+	Sequence  uint64    `json:"sequence" yaml:"sequence"`
+	LastError string    `json:"last-error" yaml:"last-error"`
+	Attempts  uint      `json:"attempts" yaml:"attempts"`
+	FailedAt  time.Time `json:"failed-at" yaml:"failed-at"`
+	NextRetry time.Time `json:"next-retry" yaml:"next-retry"`
+}
+
+// direction returns the direction of a packet or acknowledgement relayed from chain from to chain to
+func direction(from, to *Chain) string {
+	return fmt.Sprintf("%s->%s", from.ChainID, to.ChainID)
+}
+
+func (fp FailedPacket) key() string {
+	return fmt.Sprintf("%s/%s/%d", fp.Kind, fp.Direction, fp.Sequence)
+}
+
+// FailedPacketQueue is an on-disk queue of the packets and acknowledgements of a path that
+// failed to relay. The queue is stored as JSON in the failed-packets directory of the relayer
+// home and is safe for concurrent use, also by several processes such as a running relayer and
+// rly tx retry-failed.
+type FailedPacketQueue struct {
+	path string
+	file string
+
+	mu sync.Mutex
+}
+
+// NewFailedPacketQueue returns the failed packet queue of the given path
+func NewFailedPacketQueue(home, path string) *FailedPacketQueue {
+	return &FailedPacketQueue{
+		path: path,
+		file: filepath.Join(home, "failed-packets", fmt.Sprintf("%s.json", path)),
+	}
+}
+
+// Path returns the name of the path the queue belongs to
+func (q *FailedPacketQueue) Path() string {
+	return q.path
+}
+
+// List returns the packets in the queue ordered by kind, direction and sequence
+func (q *FailedPacketQueue) List() ([]FailedPacket, error) {
+	unlock, err := q.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return q.load()
+}
+
+// Failed records a failed attempt to relay the given sequences from chain from to chain to.
+// Packets that are already queued have their attempt count incremented and their next retry
+// backed off.
+func (q *FailedPacketQueue) Failed(kind string, from, to *Chain, seqs []uint64, relayErr error) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	unlock, err := q.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fps, err := q.load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	byKey := make(map[string]int, len(fps))
+	for i, fp := range fps {
+		byKey[fp.key()] = i
+	}
+
+	for _, seq := range seqs {
+		fp := FailedPacket{Path: q.path, Kind: kind, Direction: direction(from, to), Sequence: seq}
+		i, ok := byKey[fp.key()]
+		if !ok {
+			fps = append(fps, fp)
+			i = len(fps) - 1
+			byKey[fp.key()] = i
+		}
+
+		fps[i].Attempts++
+		fps[i].LastError = relayErr.Error()
+		fps[i].FailedAt = now
+		fps[i].NextRetry = now.Add(failedPacketBackoff(fps[i].Attempts))
+	}
+
+	return q.save(fps)
+}
+
+// Remove removes the given packets from the queue
+func (q *FailedPacketQueue) Remove(remove ...FailedPacket) error {
+	if len(remove) == 0 {
+		return nil
+	}
+
+	unlock, err := q.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fps, err := q.load()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool, len(remove))
+	for _, fp := range remove {
+		keys[fp.key()] = true
+	}
+
+	out := []FailedPacket{}
+	for _, fp := range fps {
+		if !keys[fp.key()] {
+			out = append(out, fp)
+		}
+	}
+
+	return q.save(out)
+}
+
+// Requeue makes every packet in the queue due for an immediate retry and returns them
+func (q *FailedPacketQueue) Requeue() ([]FailedPacket, error) {
+	unlock, err := q.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fps, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range fps {
+		fps[i].NextRetry = time.Time{}
+	}
+
+	return fps, q.save(fps)
+}
+
+// due returns the packets that are due for a retry at the given time
+func (q *FailedPacketQueue) due(now time.Time) ([]FailedPacket, error) {
+	fps, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []FailedPacket
+	for _, fp := range fps {
+		if !fp.NextRetry.After(now) {
+			out = append(out, fp)
+		}
+	}
+	return out, nil
+}
+
+// lock locks the queue for a read or read-modify-write of its file and returns the function that
+// unlocks it. Other processes are locked out by a lock on the <path>.json.lock file next to it.
+func (q *FailedPacketQueue) lock() (func(), error) {
+	q.mu.Lock()
+
+	if err := os.MkdirAll(filepath.Dir(q.file), 0700); err != nil {
+		q.mu.Unlock()
+		return nil, err
+	}
+
+	fl := flock.New(q.file + ".lock")
+	if err := fl.Lock(); err != nil {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("failed to lock failed packet queue %s: %w", q.file, err)
+	}
+
+	return func() {
+		fl.Unlock()
+		q.mu.Unlock()
+	}, nil
+}
+
+func (q *FailedPacketQueue) load() ([]FailedPacket, error) {
+	byt, err := ioutil.ReadFile(q.file)
+	switch {
+	case os.IsNotExist(err):
+		return []FailedPacket{}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	fps := []FailedPacket{}
+	if err = json.Unmarshal(byt, &fps); err != nil {
+		return nil, fmt.Errorf("failed to read failed packet queue %s: %w", q.file, err)
+	}
+	return fps, nil
+}
+
+func (q *FailedPacketQueue) save(fps []FailedPacket) error {
+	sort.Slice(fps, func(i, j int) bool {
+		if fps[i].Kind != fps[j].Kind {
+			return fps[i].Kind < fps[j].Kind
+		}
+		if fps[i].Direction != fps[j].Direction {
+			return fps[i].Direction < fps[j].Direction
+		}
+		return fps[i].Sequence < fps[j].Sequence
+	})
+
+	if len(fps) == 0 {
+		if err := os.Remove(q.file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	byt, err := json.MarshalIndent(fps, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(q.file), 0700); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so the queue is never left half written
+	tmp := q.file + ".tmp"
+	if err = ioutil.WriteFile(tmp, byt, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.file)
+}
+
+// failedPacketBackoff returns the delay before the next retry of a packet that has failed
+// the given number of times
+func failedPacketBackoff(attempts uint) time.Duration {
+	backoff := failedPacketMinBackoff
+	for i := uint(1); i < attempts && backoff < failedPacketMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > failedPacketMaxBackoff {
+		backoff = failedPacketMaxBackoff
+	}
+	return backoff
+}
+
+// RetryFailedPackets relays the packets and acknowledgements in the queue that are due for a
+// retry. Packets that are no longer unrelayed, e.g. because the sweep relayed them in the
+// meantime, are removed from the queue, as are packets that are relayed successfully. It returns the number of packets and
+// acknowledgements relayed.
+func RetryFailedPackets(ctx context.Context, src, dst *Chain, strategy Strategy,
+	q *FailedPacketQueue) (packets, acks int, err error) {
+	due, err := q.due(time.Now())
+	if err != nil || len(due) == 0 {
+		return 0, 0, err
+	}
+
+	sp, err := strategy.UnrelayedSequences(ctx, src, dst)
+	if err != nil {
+		return 0, 0, err
+	}
+	ap, err := strategy.UnrelayedAcknowledgements(ctx, src, dst)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		retryPackets, retryAcks = &RelaySequences{}, &RelaySequences{}
+		packetFPs, ackFPs       []FailedPacket
+		resolved                []FailedPacket
+	)
+	for _, fp := range due {
+		// packets are relayed from the chain that sent them, acknowledgements from the
+		// chain that wrote them
+		var (
+			unrelayed, retry *RelaySequences
+			retryFPs         *[]FailedPacket
+		)
+		switch fp.Kind {
+		case FailedPacketKindPacket:
+			unrelayed, retry, retryFPs = sp, retryPackets, &packetFPs
+		case FailedPacketKindAck:
+			unrelayed, retry, retryFPs = ap, retryAcks, &ackFPs
+		default:
+			resolved = append(resolved, fp)
+			continue
+		}
+
+		switch {
+		case fp.Direction == direction(src, dst) && containsSeq(unrelayed.Src, fp.Sequence):
+			retry.Src = append(retry.Src, fp.Sequence)
+		case fp.Direction == direction(dst, src) && containsSeq(unrelayed.Dst, fp.Sequence):
+			retry.Dst = append(retry.Dst, fp.Sequence)
+		default:
+			resolved = append(resolved, fp)
+			continue
+		}
+		*retryFPs = append(*retryFPs, fp)
+	}
+
+	if err = q.Remove(resolved...); err != nil {
+		return 0, 0, err
+	}
+
+	if len(packetFPs) > 0 {
+		if err = strategy.RelayPackets(ctx, src, dst, retryPackets); err != nil {
+			return 0, 0, q.recordRetryFailure(FailedPacketKindPacket, src, dst, retryPackets, err)
+		}
+		if err = q.Remove(packetFPs...); err != nil {
+			return 0, 0, err
+		}
+		packets = len(packetFPs)
+	}
+
+	if len(ackFPs) > 0 {
+		if err = strategy.RelayAcknowledgements(ctx, src, dst, retryAcks); err != nil {
+			return packets, 0, q.recordRetryFailure(FailedPacketKindAck, src, dst, retryAcks, err)
+		}
+		if err = q.Remove(ackFPs...); err != nil {
+			return packets, 0, err
+		}
+		acks = len(ackFPs)
+	}
+
+	return packets, acks, nil
+}
+
+// recordRetryFailure records a failed retry of the given sequences and returns relayErr
+func (q *FailedPacketQueue) recordRetryFailure(kind string, src, dst *Chain, sp *RelaySequences,
+	relayErr error) error {
+	if err := q.Failed(kind, src, dst, sp.Src, relayErr); err != nil {
+		return err
+	}
+	if err := q.Failed(kind, dst, src, sp.Dst, relayErr); err != nil {
+		return err
+	}
+	return relayErr
+}
+
+// relayerRetryLoop retries the failed packets in the queue on every tick of the retry interval
+func relayerRetryLoop(ctx, relayCtx context.Context, src, dst *Chain, strategy Strategy, q *FailedPacketQueue) {
+	ticker := time.NewTicker(FailedPacketRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			packets, acks, err := RetryFailedPackets(relayCtx, src, dst, strategy, q)
+			if err != nil {
+				src.Error(fmt.Errorf("failed to retry failed packets on path %s: %w", q.Path(), err))
+				continue
+			}
+			if packets > 0 || acks > 0 {
+				logRelayedOnRetry(src, dst, packets, acks)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func containsSeq(seqs []uint64, seq uint64) bool {
+	for _, s := range seqs {
+		if s == seq {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedPacketBackoff(t *testing.T) {
+	tests := []struct {
+		attempts uint
+		backoff  time.Duration
+	}{
+		{0, failedPacketMinBackoff},
+		{1, failedPacketMinBackoff},
+		{2, 2 * failedPacketMinBackoff},
+		{3, 4 * failedPacketMinBackoff},
+		{7, 64 * failedPacketMinBackoff},
+		{8, failedPacketMaxBackoff},
+		{100, failedPacketMaxBackoff},
+		{^uint(0), failedPacketMaxBackoff},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.backoff, failedPacketBackoff(tc.attempts), "attempts %d", tc.attempts)
+	}
+}
+
+// testRetryStrategy reports fixed unrelayed sequences and records what it is asked to relay
+type testRetryStrategy struct {
+	unrelayed, unrelayedAcks *RelaySequences
+	packets, acks            *RelaySequences
+	err                      error
+}
+
+func (s *testRetryStrategy) GetType() string {
+	return "test"
+}
+
+func (s *testRetryStrategy) HandleEvents(context.Context, *Chain, *Chain, int64, int64, map[string][]string) {
+}
+
+func (s *testRetryStrategy) UnrelayedSequences(context.Context, *Chain, *Chain) (*RelaySequences, error) {
+	return s.unrelayed, nil
+}
+
+func (s *testRetryStrategy) UnrelayedAcknowledgements(context.Context, *Chain, *Chain) (*RelaySequences, error) {
+	return s.unrelayedAcks, nil
+}
+
+func (s *testRetryStrategy) RelayPackets(_ context.Context, _, _ *Chain, sp *RelaySequences) error {
+	s.packets = sp
+	return s.err
+}
+
+func (s *testRetryStrategy) RelayAcknowledgements(_ context.Context, _, _ *Chain, sp *RelaySequences) error {
+	s.acks = sp
+	return s.err
+}
+
+func TestRetryFailedPackets(t *testing.T) {
+	var (
+		src = &Chain{ChainID: "ibc-0"}
+		dst = &Chain{ChainID: "ibc-1"}
+	)
+
+	tests := []struct {
+		name string
+		kind string
+
+		// the failed packet was relayed from chain from
+		from, to *Chain
+		seq      uint64
+
+		unrelayed, unrelayedAcks *RelaySequences
+		relayErr                 error
+
+		// the sequences relayed and whether the packet stays queued
+		packets, acks *RelaySequences
+		queued        bool
+	}{
+		{
+			name: "packet from src", kind: FailedPacketKindPacket, from: src, to: dst, seq: 1,
+			unrelayed: &RelaySequences{Src: []uint64{1}},
+			packets:   &RelaySequences{Src: []uint64{1}},
+		},
+		{
+			name: "packet from dst", kind: FailedPacketKindPacket, from: dst, to: src, seq: 1,
+			unrelayed: &RelaySequences{Dst: []uint64{1}},
+			packets:   &RelaySequences{Dst: []uint64{1}},
+		},
+		{
+			name: "ack from src", kind: FailedPacketKindAck, from: src, to: dst, seq: 2,
+			unrelayedAcks: &RelaySequences{Src: []uint64{2}},
+			acks:          &RelaySequences{Src: []uint64{2}},
+		},
+		{
+			name: "ack from dst", kind: FailedPacketKindAck, from: dst, to: src, seq: 2,
+			unrelayedAcks: &RelaySequences{Dst: []uint64{2}},
+			acks:          &RelaySequences{Dst: []uint64{2}},
+		},
+		{
+			name: "relayed in the meantime", kind: FailedPacketKindPacket, from: src, to: dst, seq: 1,
+		},
+		{
+			name: "same sequence unrelayed in the other direction", kind: FailedPacketKindPacket, from: dst, to: src, seq: 1,
+			unrelayed: &RelaySequences{Src: []uint64{1}},
+		},
+		{
+			name: "unrelayed packet doesn't keep an ack", kind: FailedPacketKindAck, from: src, to: dst, seq: 1,
+			unrelayed: &RelaySequences{Src: []uint64{1}},
+		},
+		{
+			name: "failed again", kind: FailedPacketKindPacket, from: src, to: dst, seq: 1,
+			unrelayed: &RelaySequences{Src: []uint64{1}},
+			relayErr:  errors.New("relay failed"),
+			packets:   &RelaySequences{Src: []uint64{1}},
+			queued:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := NewFailedPacketQueue(t.TempDir(), "demo")
+			require.NoError(t, q.Failed(tc.kind, tc.from, tc.to, []uint64{tc.seq}, errors.New("failed")))
+			_, err := q.Requeue()
+			require.NoError(t, err)
+
+			strategy := &testRetryStrategy{
+				unrelayed:     &RelaySequences{},
+				unrelayedAcks: &RelaySequences{},
+				err:           tc.relayErr,
+			}
+			if tc.unrelayed != nil {
+				strategy.unrelayed = tc.unrelayed
+			}
+			if tc.unrelayedAcks != nil {
+				strategy.unrelayedAcks = tc.unrelayedAcks
+			}
+
+			packets, acks, err := RetryFailedPackets(context.Background(), src, dst, strategy, q)
+			if tc.relayErr != nil {
+				require.ErrorIs(t, err, tc.relayErr)
+			} else {
+				require.NoError(t, err)
+				if tc.packets != nil {
+					require.Equal(t, 1, packets)
+				}
+				if tc.acks != nil {
+					require.Equal(t, 1, acks)
+				}
+			}
+			require.Equal(t, tc.packets, strategy.packets)
+			require.Equal(t, tc.acks, strategy.acks)
+
+			fps, err := q.List()
+			require.NoError(t, err)
+			if !tc.queued {
+				require.Empty(t, fps)
+				return
+			}
+			require.Len(t, fps, 1)
+			require.Equal(t, uint(2), fps[0].Attempts)
+			require.Equal(t, direction(tc.from, tc.to), fps[0].Direction)
+			require.True(t, fps[0].NextRetry.After(time.Now()))
+		})
+	}
+}
+
+func TestFailedPacketQueueLock(t *testing.T) {
+	var (
+		src  = &Chain{ChainID: "ibc-0"}
+		dst  = &Chain{ChainID: "ibc-1"}
+		home = t.TempDir()
+		wg   sync.WaitGroup
+	)
+
+	// each queue stands for a process of its own, e.g. a relayer and rly tx retry-failed,
+	// none of their updates may be lost
+	for i := uint64(0); i < 4; i++ {
+		wg.Add(1)
+		go func(q *FailedPacketQueue, first uint64) {
+			defer wg.Done()
+			for seq := first; seq < first+25; seq++ {
+				require.NoError(t, q.Failed(FailedPacketKindPacket, src, dst, []uint64{seq}, errors.New("failed")))
+			}
+		}(NewFailedPacketQueue(home, "demo"), i*25+1)
+	}
+	wg.Wait()
+
+	fps, err := NewFailedPacketQueue(home, "demo").List()
+	require.NoError(t, err)
+	require.Len(t, fps, 100)
+}
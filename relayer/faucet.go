@@ -1,6 +1,7 @@
 package relayer
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -15,7 +16,7 @@ import (
 func (c *Chain) SendMsgWithKey(msg sdk.Msg, keyName string) (res *sdk.TxResponse, err error) {
 	fmt.Println("setting use of key", keyName)
 	c.Key = keyName
-	res, _, err = c.SendMsg(msg)
+	res, _, err = c.SendMsg(context.Background(), msg)
 	return res, err
 
 }
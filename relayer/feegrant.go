@@ -0,0 +1,126 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+)
+
+// FeeAllowance is the fee allowance left for a key of a chain's key pool
+type FeeAllowance struct {
+	Key        string     `json:"key" yaml:"key"`
+	Grantee    string     `json:"grantee" yaml:"grantee"`
+	Granter    string     `json:"granter" yaml:"granter"`
+	SpendLimit sdk.Coins  `json:"spend-limit,omitempty" yaml:"spend-limit,omitempty"` // empty if unlimited
+	Expiration *time.Time `json:"expiration,omitempty" yaml:"expiration,omitempty"`
+	Error      string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// GetFeeGranter returns the address of the account that pays the fees of the chain's relay
+// transactions, nil if the signing keys pay their own fees
+func (c *Chain) GetFeeGranter() (sdk.AccAddress, error) {
+	if c.FeeGranter == "" {
+		return nil, nil
+	}
+	granter, err := sdk.GetFromBech32(c.FeeGranter, c.AccountPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee granter (%s) for chain %s: %w", c.FeeGranter, c.ChainID, err)
+	}
+	return granter, nil
+}
+
+// MsgGrantFeeAllowance returns the msg that grants grantee an allowance to spend up to spendLimit
+// of granter's funds on fees until expiration. An empty spendLimit or nil expiration leaves the
+// allowance unlimited.
+func (c *Chain) MsgGrantFeeAllowance(granter, grantee sdk.AccAddress, spendLimit sdk.Coins,
+	expiration *time.Time) (sdk.Msg, error) {
+	done := c.UseSDKContext()
+	defer done()
+
+	msg, err := feegrant.NewMsgGrantAllowance(&feegrant.BasicAllowance{
+		SpendLimit: spendLimit,
+		Expiration: expiration,
+	}, granter, grantee)
+	if err != nil {
+		return nil, err
+	}
+	if err = msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// QueryFeeAllowance returns the fee allowance granter granted to grantee
+func (c *Chain) QueryFeeAllowance(granter, grantee sdk.AccAddress) (feegrant.FeeAllowanceI, error) {
+	done := c.UseSDKContext()
+	req := &feegrant.QueryAllowanceRequest{Granter: granter.String(), Grantee: grantee.String()}
+	done()
+
+	res, err := feegrant.NewQueryClient(c.QueryConn(0)).Allowance(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowance feegrant.FeeAllowanceI
+	if err = c.Encoding.InterfaceRegistry.UnpackAny(res.Allowance.Allowance, &allowance); err != nil {
+		return nil, err
+	}
+	return allowance, nil
+}
+
+// QueryFeeAllowances returns the fee allowance left for every key of the chain's key pool from the
+// chain's fee granter
+func (c *Chain) QueryFeeAllowances() ([]FeeAllowance, error) {
+	granter, err := c.GetFeeGranter()
+	switch {
+	case err != nil:
+		return nil, err
+	case granter == nil:
+		return nil, fmt.Errorf("chain %s has no fee-granter configured", c.ChainID)
+	}
+
+	out := []FeeAllowance{}
+	for _, k := range c.KeyPool() {
+		addr, err := c.KeyAddress(k)
+		if err != nil {
+			return nil, err
+		}
+
+		done := c.UseSDKContext()
+		fa := FeeAllowance{Key: k, Grantee: addr.String(), Granter: granter.String()}
+		done()
+
+		allowance, err := c.QueryFeeAllowance(granter, addr)
+		if err != nil {
+			fa.Error = err.Error()
+			out = append(out, fa)
+			continue
+		}
+
+		fa.SpendLimit, fa.Expiration = allowanceLeft(allowance)
+		out = append(out, fa)
+	}
+	return out, nil
+}
+
+// allowanceLeft returns how much of the allowance can still be spent and when it expires
+func allowanceLeft(allowance feegrant.FeeAllowanceI) (sdk.Coins, *time.Time) {
+	switch a := allowance.(type) {
+	case *feegrant.BasicAllowance:
+		return a.SpendLimit, a.Expiration
+	case *feegrant.PeriodicAllowance:
+		// the periodic limit caps what can be spent in the current period
+		return a.PeriodCanSpend, a.Basic.Expiration
+	case *feegrant.AllowedMsgAllowance:
+		inner, err := a.GetAllowance()
+		if err != nil {
+			return nil, nil
+		}
+		return allowanceLeft(inner)
+	default:
+		return nil, nil
+	}
+}
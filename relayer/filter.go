@@ -0,0 +1,104 @@
+package relayer
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v2/modules/apps/transfer/types"
+)
+
+// PacketFilter defines which ICS-20 transfer packets are relayed over a path. Packets that
+// don't pass the filter are skipped by both the event listener and the sweep for unrelayed
+// packets. An empty filter relays every packet.
+type PacketFilter struct {
+	Denoms    FilterList `yaml:"denoms,omitempty" json:"denoms,omitempty"`
+	Senders   FilterList `yaml:"senders,omitempty" json:"senders,omitempty"`
+	Receivers FilterList `yaml:"receivers,omitempty" json:"receivers,omitempty"`
+	MinAmount string     `yaml:"min-amount,omitempty" json:"min-amount,omitempty"`
+}
+
+// FilterList is a list of allowed and denied values. If Allow is non-empty only the values
+// in it pass, values in Deny never pass.
+type FilterList struct {
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// Empty returns true if the list doesn't restrict any values
+func (fl FilterList) Empty() bool {
+	return len(fl.Allow) == 0 && len(fl.Deny) == 0
+}
+
+// check returns an error describing why the value doesn't pass the list
+func (fl FilterList) check(name, value string) error {
+	for _, d := range fl.Deny {
+		if d == value {
+			return fmt.Errorf("%s %s is denied", name, value)
+		}
+	}
+	if len(fl.Allow) == 0 {
+		return nil
+	}
+	for _, a := range fl.Allow {
+		if a == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s is not allowed", name, value)
+}
+
+// Empty returns true if the filter doesn't restrict any packets
+func (pf *PacketFilter) Empty() bool {
+	return pf == nil ||
+		(pf.Denoms.Empty() && pf.Senders.Empty() && pf.Receivers.Empty() && pf.MinAmount == "")
+}
+
+// Validate checks that the filter is well formed
+func (pf *PacketFilter) Validate() error {
+	if pf == nil || pf.MinAmount == "" {
+		return nil
+	}
+	if _, ok := sdk.NewIntFromString(pf.MinAmount); !ok {
+		return fmt.Errorf("invalid packet filter min-amount: %s", pf.MinAmount)
+	}
+	return nil
+}
+
+// Check returns an error describing why the packet with the given data doesn't pass the
+// filter, or nil if it should be relayed
+func (pf *PacketFilter) Check(packetData []byte) error {
+	if pf.Empty() {
+		return nil
+	}
+
+	var data transfertypes.FungibleTokenPacketData
+	if err := transfertypes.ModuleCdc.UnmarshalJSON(packetData, &data); err != nil {
+		return fmt.Errorf("packet data is not an ICS-20 transfer: %w", err)
+	}
+
+	if err := pf.Denoms.check("denom", data.Denom); err != nil {
+		return err
+	}
+	if err := pf.Senders.check("sender", data.Sender); err != nil {
+		return err
+	}
+	if err := pf.Receivers.check("receiver", data.Receiver); err != nil {
+		return err
+	}
+
+	if pf.MinAmount != "" {
+		min, ok := sdk.NewIntFromString(pf.MinAmount)
+		if !ok {
+			return fmt.Errorf("invalid packet filter min-amount: %s", pf.MinAmount)
+		}
+		amount, ok := sdk.NewIntFromString(data.Amount)
+		if !ok {
+			return fmt.Errorf("invalid packet amount: %s", data.Amount)
+		}
+		if amount.LT(min) {
+			return fmt.Errorf("amount %s%s is below the minimum of %s", data.Amount, data.Denom, pf.MinAmount)
+		}
+	}
+
+	return nil
+}
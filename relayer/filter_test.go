@@ -0,0 +1,69 @@
+package relayer
+
+import (
+	"testing"
+
+	transfertypes "github.com/cosmos/ibc-go/v2/modules/apps/transfer/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketFilterCheck(t *testing.T) {
+	var (
+		transfer = transfertypes.NewFungibleTokenPacketData("uatom", "100", "alice", "bob").GetBytes()
+		invalid  = transfertypes.NewFungibleTokenPacketData("uatom", "lots", "alice", "bob").GetBytes()
+	)
+
+	tests := []struct {
+		name   string
+		filter *PacketFilter
+		data   []byte
+		pass   bool
+	}{
+		{"nil filter", nil, transfer, true},
+		{"empty filter", &PacketFilter{}, []byte("not a transfer"), true},
+		{"not a transfer", &PacketFilter{MinAmount: "1"}, []byte("not a transfer"), false},
+		{"allowed denom", &PacketFilter{Denoms: FilterList{Allow: []string{"uosmo", "uatom"}}}, transfer, true},
+		{"not allowed denom", &PacketFilter{Denoms: FilterList{Allow: []string{"uosmo"}}}, transfer, false},
+		{"denied denom", &PacketFilter{Denoms: FilterList{Deny: []string{"uatom"}}}, transfer, false},
+		{"other denied denom", &PacketFilter{Denoms: FilterList{Deny: []string{"uosmo"}}}, transfer, true},
+		{"denied over allowed", &PacketFilter{Denoms: FilterList{Allow: []string{"uatom"}, Deny: []string{"uatom"}}},
+			transfer, false},
+		{"allowed sender", &PacketFilter{Senders: FilterList{Allow: []string{"alice"}}}, transfer, true},
+		{"denied sender", &PacketFilter{Senders: FilterList{Deny: []string{"alice"}}}, transfer, false},
+		{"sender list doesn't match receiver", &PacketFilter{Senders: FilterList{Allow: []string{"bob"}}}, transfer, false},
+		{"allowed receiver", &PacketFilter{Receivers: FilterList{Allow: []string{"bob"}}}, transfer, true},
+		{"denied receiver", &PacketFilter{Receivers: FilterList{Deny: []string{"bob"}}}, transfer, false},
+		{"above min amount", &PacketFilter{MinAmount: "99"}, transfer, true},
+		{"at min amount", &PacketFilter{MinAmount: "100"}, transfer, true},
+		{"below min amount", &PacketFilter{MinAmount: "101"}, transfer, false},
+		{"invalid min amount", &PacketFilter{MinAmount: "some"}, transfer, false},
+		{"invalid amount", &PacketFilter{MinAmount: "1"}, invalid, false},
+		{"every list passes", &PacketFilter{
+			Denoms:    FilterList{Allow: []string{"uatom"}},
+			Senders:   FilterList{Deny: []string{"mallory"}},
+			Receivers: FilterList{Allow: []string{"bob"}},
+			MinAmount: "10",
+		}, transfer, true},
+		{"one list fails", &PacketFilter{
+			Denoms:    FilterList{Allow: []string{"uatom"}},
+			Receivers: FilterList{Allow: []string{"carol"}},
+		}, transfer, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.filter.Check(tc.data)
+			if tc.pass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPacketFilterValidate(t *testing.T) {
+	require.NoError(t, (*PacketFilter)(nil).Validate())
+	require.NoError(t, (&PacketFilter{}).Validate())
+	require.NoError(t, (&PacketFilter{MinAmount: "1000"}).Validate())
+	require.Error(t, (&PacketFilter{MinAmount: "1k"}).Validate())
+}
@@ -0,0 +1,165 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	sdkCtx "github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ErrGenerateOnlyHandshake is returned when a handshake is retried until it completes in generate
+// only mode. Each run of a handshake writes only its next step, which has to be signed and
+// broadcast before the run after it can build the one that follows.
+var ErrGenerateOnlyHandshake = errors.New(
+	"handshakes generate one step per run, each step needs the on-chain result of the one before")
+
+// SetGenerateOnly makes the chain write its transactions unsigned to files in dir instead of
+// signing and broadcasting them. An empty dir turns generate only mode off.
+func (c *Chain) SetGenerateOnly(dir string) {
+	c.generateDir = dir
+}
+
+// GenerateOnly returns the directory unsigned transactions are written to, empty if the chain
+// broadcasts its transactions
+func (c *Chain) GenerateOnly() string {
+	return c.generateDir
+}
+
+// broadcasts returns false if the chain's transactions are simulated or generated instead of
+// being broadcast, in which case they don't change any state
+func (c *Chain) broadcasts() bool {
+	return !c.dryRun && c.generateDir == ""
+}
+
+// logNextStep tells the operator how to continue a handshake that stopped after writing one of
+// its steps in generate only mode
+func (c *Chain) logNextStep(handshake string) {
+	if c.generateDir == "" {
+		return
+	}
+	c.Log(fmt.Sprintf("- sign and broadcast the generated tx with 'rly tx broadcast', then run the "+
+		"command again for the next %s handshake step", handshake))
+}
+
+// generateMsgs builds the unsigned transaction the msgs would be sent in and writes its JSON to
+// the next free <chain-id>-<n>.json file in the generate only directory, so it can be signed
+// offline, e.g. by the members of a multisig account, and submitted with BroadcastSignedTx
+func (c *Chain) generateMsgs(msgs []sdk.Msg) (*sdk.TxResponse, bool, error) {
+	msgs, err := c.wrapAuthz(msgs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cliCtx := c.CLIContext(0)
+
+	seq := c.accountSequence()
+	seq.mu.Lock()
+	txf, err := seq.factory(cliCtx, c.TxFactory(0))
+	seq.mu.Unlock()
+	if err != nil {
+		return nil, false, err
+	}
+
+	txf, txb, err := c.buildUnsignedTx(cliCtx, txf, msgs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	bz, err := cliCtx.TxConfig.TxJSONEncoder()(txb.GetTx())
+	if err != nil {
+		return nil, false, err
+	}
+
+	file, err := c.writeUnsignedTx(bz)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.Log(fmt.Sprintf("- [%s] wrote unsigned tx with %d msgs to %s", c.ChainID, len(msgs), file))
+	return &sdk.TxResponse{GasWanted: int64(txf.Gas())}, true, nil
+}
+
+// writeUnsignedTx writes bz to the first <chain-id>-<n>.json file that doesn't exist yet in the
+// generate only directory and returns its path
+func (c *Chain) writeUnsignedTx(bz []byte) (string, error) {
+	if err := os.MkdirAll(c.generateDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	for n := 1; ; n++ {
+		file := path.Join(c.generateDir, fmt.Sprintf("%s-%d.json", c.ChainID, n))
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		switch {
+		case os.IsExist(err):
+			continue
+		case err != nil:
+			return "", err
+		}
+
+		if _, err = f.Write(bz); err != nil {
+			f.Close()
+			return "", err
+		}
+		return file, f.Close()
+	}
+}
+
+// BroadcastSignedTx broadcasts the signed transaction JSON in file, as written by generate only
+// mode and signed offline, and waits for it to be committed, in commit mode or by polling if the
+// chain has a confirm timeout. A boolean indicating if the
+// transaction was executed successfully is returned.
+func (c *Chain) BroadcastSignedTx(ctx context.Context, file string) (*sdk.TxResponse, bool, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cliCtx := c.CLIContext(0)
+	signed, err := cliCtx.TxConfig.TxJSONDecoder()(bz)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode signed tx %s: %w", file, err)
+	}
+	txBytes, err := cliCtx.TxConfig.TxEncoder()(signed)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the transaction may use up a sequence of the chain's key
+	defer c.accountSequence().resync()
+
+	timeout := c.GetConfirmTimeout()
+	if timeout == 0 {
+		res, err := c.Client.BroadcastTxCommit(ctx, txBytes)
+		if err != nil {
+			if errRes := sdkCtx.CheckTendermintError(err, txBytes); errRes != nil {
+				return errRes, false, nil
+			}
+			return nil, false, err
+		}
+		out := sdk.NewResponseFormatBroadcastTxCommit(res)
+		return out, out.Code == 0, nil
+	}
+
+	res, err := c.Client.BroadcastTxSync(ctx, txBytes)
+	if err != nil {
+		if errRes := sdkCtx.CheckTendermintError(err, txBytes); errRes != nil {
+			return errRes, false, nil
+		}
+		return nil, false, err
+	}
+
+	out := sdk.NewResponseFormatBroadcastTx(res)
+	if out.Code != 0 {
+		return out, false, nil
+	}
+
+	if out, err = c.confirmTx(ctx, out.TxHash, timeout); err != nil {
+		return nil, false, err
+	}
+	return out, out.Code == 0, nil
+}
@@ -0,0 +1,30 @@
+package relayer
+
+import (
+	"net/http"
+)
+
+// ChainHealth reports the status of the relayer's connection to a chain
+type ChainHealth struct {
+	ChainID    string `json:"chain-id"`
+	Subscribed bool   `json:"subscribed"`
+}
+
+// HealthHandler returns the subscription status of each of the given chains. The response
+// code is 503 if any of the chains doesn't have live event subscriptions.
+func HealthHandler(chains ...*Chain) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			code   = http.StatusOK
+			health = make([]ChainHealth, 0, len(chains))
+		)
+		for _, c := range chains {
+			live := c.SubscriptionLive()
+			if !live {
+				code = http.StatusServiceUnavailable
+			}
+			health = append(health, ChainHealth{ChainID: c.ChainID, Subscribed: live})
+		}
+		respondWithJSON(w, code, health)
+	}
+}
@@ -11,6 +11,10 @@ import (
 )
 
 func (c *Chain) GetLightSignedHeaderAtHeight(h int64) (*tmclient.Header, error) {
+	if header, ok := c.cachedHeader(h); ok {
+		return header, nil
+	}
+
 	lightBlock, err := c.Provider.LightBlock(context.Background(), h)
 	if err != nil {
 		return nil, err
@@ -20,10 +24,12 @@ func (c *Chain) GetLightSignedHeaderAtHeight(h int64) (*tmclient.Header, error)
 		return nil, err
 	}
 
-	return &tmclient.Header{
+	header := &tmclient.Header{
 		SignedHeader: lightBlock.SignedHeader.ToProto(),
 		ValidatorSet: protoVal,
-	}, nil
+	}
+	c.cacheHeader(h, header)
+	return header, nil
 }
 
 // GetIBCUpdateHeader updates the off chain tendermint light client and
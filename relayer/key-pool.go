@@ -0,0 +1,207 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+var (
+	// KeyBalanceCheckInterval is how long the result of checking whether a key in a chain's
+	// pool can pay fees is cached for
+	KeyBalanceCheckInterval = time.Minute
+
+	// maximum number of mempool txs inspected when counting pending txs
+	pendingTxsLimit = 100
+)
+
+// keyPool holds the account sequences of the keys a chain signs relay transactions with and
+// dispatches relay transactions across them round-robin
+type keyPool struct {
+	mu sync.Mutex
+
+	next     int
+	seqs     map[string]*accountSequence
+	balances map[string]keyBalance
+}
+
+// keyBalance is the cached result of checking whether a key can pay fees
+type keyBalance struct {
+	canPay    bool
+	checkedAt time.Time
+}
+
+func newKeyPool() *keyPool {
+	return &keyPool{
+		seqs:     make(map[string]*accountSequence),
+		balances: make(map[string]keyBalance),
+	}
+}
+
+// keyPool returns the key pool of the chain
+func (c *Chain) keyPool() *keyPool {
+	// chains that weren't initialized get a pool that isn't shared with their copies
+	if c.pool == nil {
+		c.pool = newKeyPool()
+	}
+	return c.pool
+}
+
+// accountSequence returns the account sequence of the chain's key
+func (c *Chain) accountSequence() *accountSequence {
+	pool := c.keyPool()
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	seq, ok := pool.seqs[c.Key]
+	if !ok {
+		seq = &accountSequence{}
+		pool.seqs[c.Key] = seq
+	}
+	return seq
+}
+
+// KeyPool returns the names of the keys relay transactions are signed with, the chain's key first
+func (c *Chain) KeyPool() []string {
+	out := []string{c.Key}
+	seen := map[string]bool{c.Key: true}
+	for _, k := range c.Keys {
+		if !seen[k] {
+			out = append(out, k)
+			seen[k] = true
+		}
+	}
+	return out
+}
+
+// nextSigner returns a copy of the chain that signs with the next key of its pool that can pay
+// fees. Chains without additional keys, or whose keys can't pay fees, sign with their key.
+func (c *Chain) nextSigner() *Chain {
+	keys := c.KeyPool()
+	if len(keys) == 1 {
+		return c
+	}
+
+	pool := c.keyPool()
+	for range keys {
+		pool.mu.Lock()
+		name := keys[pool.next%len(keys)]
+		pool.next++
+		pool.mu.Unlock()
+
+		if c.canPayFees(name) {
+			return c.WithKey(name)
+		}
+	}
+	return c
+}
+
+// WithKey returns a copy of the chain that signs with the given key
+func (c *Chain) WithKey(name string) *Chain {
+	if name == c.Key {
+		return c
+	}
+	out := *c
+	out.Key = name
+	out.address = nil
+	return &out
+}
+
+// canPayFees returns true if the given key holds any of the denoms of the chain's gas prices, or
+// has a fee allowance from the chain's fee granter. The result is cached for KeyBalanceCheckInterval.
+func (c *Chain) canPayFees(name string) bool {
+	pool := c.keyPool()
+	pool.mu.Lock()
+	bal, ok := pool.balances[name]
+	pool.mu.Unlock()
+	if ok && time.Since(bal.checkedAt) < KeyBalanceCheckInterval {
+		return bal.canPay
+	}
+
+	bal = keyBalance{canPay: c.keyHasFeeDenom(name), checkedAt: time.Now()}
+	pool.mu.Lock()
+	pool.balances[name] = bal
+	pool.mu.Unlock()
+
+	if !bal.canPay {
+		c.Log(fmt.Sprintf("- [%s] key %s can't pay fees, skipping it", c.ChainID, name))
+	}
+	return bal.canPay
+}
+
+func (c *Chain) keyHasFeeDenom(name string) bool {
+	if granter, err := c.GetFeeGranter(); err == nil && granter != nil {
+		addr, err := c.KeyAddress(name)
+		if err != nil {
+			c.Error(err)
+			return false
+		}
+		if _, err = c.QueryFeeAllowance(granter, addr); err != nil {
+			c.Error(fmt.Errorf("failed to query fee allowance of key %s: %w", name, err))
+			return false
+		}
+		return true
+	}
+
+	gasPrices, err := sdk.ParseDecCoins(c.GasPrices)
+	if err != nil || gasPrices.IsZero() {
+		return true
+	}
+
+	coins, err := c.QueryBalance(name)
+	if err != nil {
+		c.Error(fmt.Errorf("failed to query balance of key %s: %w", name, err))
+		return false
+	}
+
+	for _, gp := range gasPrices {
+		if coins.AmountOf(gp.Denom).IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryPendingTxs returns the number of transactions signed by each key of the chain's pool that
+// are in the mempool of the chain's RPC node
+func (c *Chain) QueryPendingTxs() (map[string]int, error) {
+	res, err := c.Client.UnconfirmedTxs(context.Background(), &pendingTxsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// map the addresses of the pool's keys to their names
+	names := make(map[string]string)
+	for _, k := range c.KeyPool() {
+		addr, err := c.KeyAddress(k)
+		if err != nil {
+			return nil, err
+		}
+		names[string(addr)] = k
+	}
+
+	out := make(map[string]int)
+	for _, k := range c.KeyPool() {
+		out[k] = 0
+	}
+	for _, txBytes := range res.Txs {
+		tx, err := c.Encoding.TxConfig.TxDecoder()(txBytes)
+		if err != nil {
+			continue
+		}
+		sigTx, ok := tx.(authsigning.SigVerifiableTx)
+		if !ok {
+			continue
+		}
+		for _, signer := range sigTx.GetSigners() {
+			if name, ok := names[string(signer)]; ok {
+				out[name]++
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,143 @@
+package relayer
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	tmclient "github.com/cosmos/ibc-go/v2/modules/light-clients/07-tendermint/types"
+	lru "github.com/hashicorp/golang-lru"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+// DefaultLightCacheSize is the number of signed headers and validator sets kept in the light
+// cache until SetLightCacheSize is called
+const DefaultLightCacheSize = 20
+
+// lightCache holds the signed headers and validator sets of all chains by chain ID and height,
+// so every strategy and client update loop of the process shares it
+var lightCache = newLightCache()
+
+// lightCacheKey is the key of a signed header or validator set in the light cache
+type lightCacheKey struct {
+	valset  bool
+	chainID string
+	height  int64
+}
+
+type lightCacheStore struct {
+	entries *lru.Cache
+
+	// size is 0 when caching is disabled
+	size   int64
+	hits   uint64
+	misses uint64
+}
+
+// LightCacheStats are the size and hit and miss counts of the light cache
+type LightCacheStats struct {
+	Size    int    `json:"size" yaml:"size"`
+	Entries int    `json:"entries" yaml:"entries"`
+	Hits    uint64 `json:"hits" yaml:"hits"`
+	Misses  uint64 `json:"misses" yaml:"misses"`
+}
+
+func newLightCache() *lightCacheStore {
+	entries, err := lru.New(DefaultLightCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &lightCacheStore{entries: entries, size: DefaultLightCacheSize}
+}
+
+// SetLightCacheSize sets the number of signed headers and validator sets kept in the light
+// cache, evicting the least recently used ones if it shrinks. An unset size of 0 keeps
+// DefaultLightCacheSize and a negative size disables the cache.
+func SetLightCacheSize(size int) {
+	if size == 0 {
+		size = DefaultLightCacheSize
+	}
+	if size < 0 {
+		atomic.StoreInt64(&lightCache.size, 0)
+		lightCache.entries.Purge()
+		return
+	}
+	lightCache.entries.Resize(size)
+	atomic.StoreInt64(&lightCache.size, int64(size))
+}
+
+// GetLightCacheStats returns the size and hit and miss counts of the light cache
+func GetLightCacheStats() LightCacheStats {
+	return LightCacheStats{
+		Size:    int(atomic.LoadInt64(&lightCache.size)),
+		Entries: lightCache.entries.Len(),
+		Hits:    atomic.LoadUint64(&lightCache.hits),
+		Misses:  atomic.LoadUint64(&lightCache.misses),
+	}
+}
+
+// LightCacheHandler returns the stats of the light cache
+func LightCacheHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, GetLightCacheStats())
+}
+
+func (lc *lightCacheStore) get(key lightCacheKey) (interface{}, bool) {
+	if atomic.LoadInt64(&lc.size) == 0 {
+		return nil, false
+	}
+
+	v, ok := lc.entries.Get(key)
+	if ok {
+		atomic.AddUint64(&lc.hits, 1)
+	} else {
+		atomic.AddUint64(&lc.misses, 1)
+	}
+	return v, ok
+}
+
+func (lc *lightCacheStore) add(key lightCacheKey, v interface{}) {
+	if atomic.LoadInt64(&lc.size) == 0 {
+		return
+	}
+	lc.entries.Add(key, v)
+}
+
+// cachedHeader returns a copy of the cached signed header of the chain at height. The latest
+// header, height 0, is never cached.
+func (c *Chain) cachedHeader(height int64) (*tmclient.Header, bool) {
+	if height <= 0 {
+		return nil, false
+	}
+
+	v, ok := lightCache.get(lightCacheKey{chainID: c.ChainID, height: height})
+	if !ok {
+		return nil, false
+	}
+
+	// callers may set the trusted fields of the header they get
+	h := *(v.(*tmclient.Header))
+	return &h, true
+}
+
+// cacheHeader adds the signed header of the chain at height to the light cache
+func (c *Chain) cacheHeader(height int64, h *tmclient.Header) {
+	if height <= 0 {
+		return
+	}
+
+	cp := *h
+	lightCache.add(lightCacheKey{chainID: c.ChainID, height: height}, &cp)
+}
+
+// cachedValset returns the cached validator set of the chain at height
+func (c *Chain) cachedValset(height int64) (*tmproto.ValidatorSet, bool) {
+	v, ok := lightCache.get(lightCacheKey{valset: true, chainID: c.ChainID, height: height})
+	if !ok {
+		return nil, false
+	}
+	return v.(*tmproto.ValidatorSet), true
+}
+
+// cacheValset adds the validator set of the chain at height to the light cache
+func (c *Chain) cacheValset(height int64, vs *tmproto.ValidatorSet) {
+	lightCache.add(lightCacheKey{valset: true, chainID: c.ChainID, height: height}, vs)
+}
@@ -0,0 +1,71 @@
+package relayer
+
+import (
+	"testing"
+
+	tmclient "github.com/cosmos/ibc-go/v2/modules/light-clients/07-tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLightCacheSize(t *testing.T) {
+	defer SetLightCacheSize(DefaultLightCacheSize)
+
+	c := &Chain{ChainID: "ibc-0"}
+	fill := func(n int) {
+		for h := int64(1); h <= int64(n); h++ {
+			c.cacheHeader(h, &tmclient.Header{})
+		}
+	}
+
+	tests := []struct {
+		name string
+		size int
+
+		// entries are added up to height fill, heights up to evicted are no longer cached
+		fill    int
+		evicted int64
+		stats   LightCacheStats
+	}{
+		{"default", 0, 30, 10, LightCacheStats{Size: DefaultLightCacheSize, Entries: DefaultLightCacheSize}},
+		{"grow", 50, 30, 0, LightCacheStats{Size: 50, Entries: 30}},
+		{"shrink", 5, 30, 25, LightCacheStats{Size: 5, Entries: 5}},
+		{"disabled", -1, 30, 30, LightCacheStats{Size: 0, Entries: 0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetLightCacheSize(DefaultLightCacheSize)
+			lightCache.entries.Purge()
+			fill(DefaultLightCacheSize)
+
+			SetLightCacheSize(tc.size)
+			fill(tc.fill)
+
+			stats := GetLightCacheStats()
+			require.Equal(t, tc.stats.Size, stats.Size)
+			require.Equal(t, tc.stats.Entries, stats.Entries)
+
+			for h := int64(1); h <= int64(tc.fill); h++ {
+				_, ok := c.cachedHeader(h)
+				require.Equal(t, h > tc.evicted, ok, "height %d", h)
+			}
+		})
+	}
+}
+
+func TestLightCacheLatestHeader(t *testing.T) {
+	defer SetLightCacheSize(DefaultLightCacheSize)
+	SetLightCacheSize(DefaultLightCacheSize)
+
+	c := &Chain{ChainID: "ibc-0"}
+	c.cacheHeader(0, &tmclient.Header{})
+	_, ok := c.cachedHeader(0)
+	require.False(t, ok)
+
+	// cached headers are copies, so callers can't modify the cached one
+	c.cacheHeader(1, &tmclient.Header{})
+	h, ok := c.cachedHeader(1)
+	require.True(t, ok)
+	h.TrustedHeight.RevisionHeight = 5
+	h, _ = c.cachedHeader(1)
+	require.Zero(t, h.TrustedHeight.RevisionHeight)
+}
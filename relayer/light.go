@@ -0,0 +1,345 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	retry "github.com/avast/retry-go"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	provtypes "github.com/tendermint/tendermint/light/provider"
+	lightdb "github.com/tendermint/tendermint/light/store/db"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// ErrLightNotInitialized is returned when the light client database of a chain doesn't exist
+var ErrLightNotInitialized = errors.New("light client database is not initialized")
+
+// LightStatus describes the trusted light blocks in a chain's light client database
+type LightStatus struct {
+	ChainID     string    `json:"chain-id" yaml:"chain-id"`
+	Path        string    `json:"path" yaml:"path"`
+	FirstHeight int64     `json:"first-height" yaml:"first-height"`
+	LastHeight  int64     `json:"last-height" yaml:"last-height"`
+	LastHash    string    `json:"last-hash" yaml:"last-hash"`
+	LastTime    time.Time `json:"last-time" yaml:"last-time"`
+}
+
+func lightDir(home string) string {
+	return path.Join(home, "light")
+}
+
+// lightProvider provides the light blocks of a chain once they are verified by bisection from the
+// last trusted block of the chain's light client database, which stores them. Until the database
+// is initialized the light blocks of its primary provider are returned unverified.
+type lightProvider struct {
+	chainID        string
+	dir            string
+	trustingPeriod time.Duration
+	primary        provtypes.Provider
+	logger         log.Logger
+
+	// chainLogger logs that light blocks aren't verified, once
+	chainLogger log.Logger
+	warnOnce    sync.Once
+
+	// mu serializes the access to the light client database, which is opened on first use and
+	// kept open with its light client until Close
+	mu sync.Mutex
+	db dbm.DB
+	lc *light.Client
+}
+
+var _ provtypes.Provider = &lightProvider{}
+
+func newLightProvider(chainID, dir string, trustingPeriod time.Duration, primary provtypes.Provider,
+	logger, chainLogger log.Logger) *lightProvider {
+	return &lightProvider{
+		chainID:        chainID,
+		dir:            dir,
+		trustingPeriod: trustingPeriod,
+		primary:        primary,
+		logger:         logger,
+		chainLogger:    chainLogger,
+	}
+}
+
+// ChainID implements provider.Provider
+func (lp *lightProvider) ChainID() string {
+	return lp.chainID
+}
+
+// LightBlock implements provider.Provider
+func (lp *lightProvider) LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	if !lp.initialized() {
+		lp.warnOnce.Do(func() {
+			lp.chainLogger.Error(fmt.Sprintf("light blocks of chain %s aren't verified, run 'rly light init %s' to "+
+				"initialize its light client database", lp.chainID, lp.chainID))
+		})
+		return lp.primary.LightBlock(ctx, height)
+	}
+	return lp.verifiedLightBlock(ctx, height)
+}
+
+// verifiedLightBlock returns the light block at height, or the latest one with a height of 0,
+// verified from the trusted light blocks of the light client database. It returns
+// ErrLightNotInitialized if the database doesn't exist.
+func (lp *lightProvider) verifiedLightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	if !lp.initialized() {
+		return nil, lp.errNotInitialized()
+	}
+
+	var lb *tmtypes.LightBlock
+	err := lp.withDB(func(db dbm.DB) (err error) {
+		lc, err := lp.client(db)
+		if err != nil {
+			return err
+		}
+
+		// a light client drops a primary that fails to serve a block, the next call restores
+		// a new one from the trusted store
+		defer func() {
+			if err != nil {
+				lp.lc = nil
+			}
+		}()
+
+		if height != 0 {
+			lb, err = lc.VerifyLightBlockAtHeight(ctx, height, time.Now())
+			return err
+		}
+
+		// Update returns nothing if the latest block is already trusted
+		if lb, err = lc.Update(ctx, time.Now()); err != nil || lb != nil {
+			return err
+		}
+		lb, err = lc.TrustedLightBlock(0)
+		return err
+	})
+	return lb, err
+}
+
+// errNotInitialized returns ErrLightNotInitialized with how to initialize the database
+func (lp *lightProvider) errNotInitialized() error {
+	return fmt.Errorf("%w for chain %s, run 'rly light init %s' first", ErrLightNotInitialized, lp.chainID, lp.chainID)
+}
+
+// ReportEvidence implements provider.Provider
+func (lp *lightProvider) ReportEvidence(ctx context.Context, ev tmtypes.Evidence) error {
+	return lp.primary.ReportEvidence(ctx, ev)
+}
+
+func (lp *lightProvider) dbPath() string {
+	return path.Join(lp.dir, lp.chainID+".db")
+}
+
+func (lp *lightProvider) initialized() bool {
+	_, err := os.Stat(lp.dbPath())
+	return err == nil
+}
+
+// withDB calls fn with the light client database while holding the provider's lock, opening it
+// first, and creating it if it doesn't exist, unless it is already open
+func (lp *lightProvider) withDB(fn func(db dbm.DB) error) error {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+
+	if lp.db == nil {
+		// another process, e.g. rly light show, may have the database open for a moment
+		var db *dbm.GoLevelDB
+		if err := retry.Do(func() (err error) {
+			db, err = dbm.NewGoLevelDB(lp.chainID, lp.dir)
+			return err
+		}, RtyAtt, RtyDel, RtyErr); err != nil {
+			return fmt.Errorf("failed to open light client database of chain %s, is it in use by "+
+				"another relayer process? %w", lp.chainID, err)
+		}
+		lp.db = db
+	}
+
+	return fn(lp.db)
+}
+
+// Close closes the light client database if it is open. It is opened again on the next use.
+func (lp *lightProvider) Close() error {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	return lp.close()
+}
+
+// close is Close with the provider's lock held
+func (lp *lightProvider) close() error {
+	if lp.db == nil {
+		return nil
+	}
+	err := lp.db.Close()
+	lp.db, lp.lc = nil, nil
+	return err
+}
+
+// client returns the light client restored from the trusted store of db, which is kept until the
+// database is closed. It must be called from withDB.
+func (lp *lightProvider) client(db dbm.DB) (*light.Client, error) {
+	if lp.lc != nil {
+		return lp.lc, nil
+	}
+
+	// the primary is its own witness, forged headers are caught by verifying them against
+	// the trusted validator sets
+	lc, err := light.NewClientFromTrustedStore(
+		lp.chainID,
+		lp.trustingPeriod,
+		lp.primary,
+		[]provtypes.Provider{lp.primary},
+		lightdb.New(db, lp.chainID),
+		lp.options()...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	lp.lc = lc
+	return lc, nil
+}
+
+// options returns the options of the light clients of the provider. Light blocks are never
+// pruned, verifying a header older than the first trusted block would otherwise take a
+// backwards verification from it.
+func (lp *lightProvider) options() []light.Option {
+	return []light.Option{light.Logger(lp.logger), light.PruningSize(0)}
+}
+
+func (c *Chain) lightProvider() (*lightProvider, error) {
+	lp, ok := c.Provider.(*lightProvider)
+	if !ok {
+		return nil, fmt.Errorf("chain %s has no light provider, it isn't initialized", c.ChainID)
+	}
+	return lp, nil
+}
+
+// InitLight creates or resets the chain's light client database, trusting the light block at the
+// given height if its hash matches. With a height of 0 and no hash the latest light block of the
+// chain's RPC endpoint is trusted as is.
+func (c *Chain) InitLight(height int64, hash []byte) (*tmtypes.LightBlock, error) {
+	lp, err := c.lightProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	if height == 0 && len(hash) == 0 {
+		lb, err := lp.primary.LightBlock(context.Background(), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest light block of chain %s: %w", c.ChainID, err)
+		}
+		height, hash = lb.Height, lb.Hash()
+	}
+
+	opts := light.TrustOptions{Period: lp.trustingPeriod, Height: height, Hash: hash}
+	if err = opts.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid trust options for chain %s: %w", c.ChainID, err)
+	}
+
+	if err = os.MkdirAll(lp.dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	existed := lp.initialized()
+
+	var lb *tmtypes.LightBlock
+	err = lp.withDB(func(db dbm.DB) error {
+		lc, err := light.NewClient(
+			context.Background(),
+			lp.chainID,
+			opts,
+			lp.primary,
+			[]provtypes.Provider{lp.primary},
+			lightdb.New(db, lp.chainID),
+			lp.options()...,
+		)
+		if err != nil {
+			return err
+		}
+		lp.lc = lc
+		lb, err = lc.TrustedLightBlock(height)
+		return err
+	})
+	if err != nil {
+		// a database without a trusted light block can't verify anything
+		if !existed {
+			lp.Close()
+			os.RemoveAll(lp.dbPath())
+		}
+		return nil, fmt.Errorf("failed to initialize light client database of chain %s: %w", c.ChainID, err)
+	}
+	return lb, nil
+}
+
+// LightStatus returns the trusted light blocks held by the chain's light client database
+func (c *Chain) LightStatus() (*LightStatus, error) {
+	lp, err := c.lightProvider()
+	if err != nil {
+		return nil, err
+	}
+	if !lp.initialized() {
+		return nil, fmt.Errorf("%w for chain %s", ErrLightNotInitialized, c.ChainID)
+	}
+
+	out := &LightStatus{ChainID: c.ChainID, Path: lp.dbPath()}
+	err = lp.withDB(func(db dbm.DB) error {
+		var err error
+		store := lightdb.New(db, lp.chainID)
+		if out.FirstHeight, err = store.FirstLightBlockHeight(); err != nil {
+			return err
+		}
+		if out.LastHeight, err = store.LastLightBlockHeight(); err != nil {
+			return err
+		}
+		if out.LastHeight <= 0 {
+			return nil
+		}
+
+		lb, err := store.LightBlock(out.LastHeight)
+		if err != nil {
+			return err
+		}
+		out.LastHash = lb.Hash().String()
+		out.LastTime = lb.Time
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteLight deletes the chain's light client database, so its light blocks aren't verified
+// until it is initialized again
+func (c *Chain) DeleteLight() error {
+	lp, err := c.lightProvider()
+	if err != nil {
+		return err
+	}
+	if !lp.initialized() {
+		return fmt.Errorf("%w for chain %s", ErrLightNotInitialized, c.ChainID)
+	}
+
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+	if err = lp.close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(lp.dbPath())
+}
+
+// Close closes the chain's light client database if it is open
+func (c *Chain) Close() error {
+	lp, ok := c.Provider.(*lightProvider)
+	if !ok {
+		return nil
+	}
+	return lp.Close()
+}
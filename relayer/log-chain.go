@@ -47,6 +47,36 @@ func (c *Chain) logPacketsRelayed(dst *Chain, num int) {
 		num, dst.ChainID, dst.PathEnd.PortID, c.ChainID, c.PathEnd.PortID))
 }
 
+func (c *Chain) logPacketSkipped(seq uint64, reason error) {
+	c.Log(fmt.Sprintf("- [%s] skipping packet seq(%d) sent on port{%s} chan{%s}: %s",
+		c.ChainID, seq, c.PathEnd.PortID, c.PathEnd.ChannelID, reason))
+}
+
+func (c *Chain) logPacketGap(next, seq uint64) {
+	c.Log(fmt.Sprintf("- [%s] waiting for packet seq(%d) on ordered chan{%s} before relaying seq(%d) and later",
+		c.ChainID, next, c.PathEnd.ChannelID, seq))
+}
+
+func logRelayedOnStartup(src, dst *Chain, packets, acks int) {
+	src.Log(fmt.Sprintf("★ Relayed %d packets and %d acknowledgements on startup: [%s]port{%s}<->[%s]port{%s}",
+		packets, acks, src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID))
+}
+
+func logRelayedOnSweep(src, dst *Chain, packets, acks int) {
+	src.Log(fmt.Sprintf("★ Relayed %d packets and %d acknowledgements on sweep: [%s]port{%s}<->[%s]port{%s}",
+		packets, acks, src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID))
+}
+
+func logRelayedOnReconnect(src, dst *Chain, packets, acks int) {
+	src.Log(fmt.Sprintf("★ Relayed %d packets and %d acknowledgements after reconnect: [%s]port{%s}<->[%s]port{%s}",
+		packets, acks, src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID))
+}
+
+func logRelayedOnRetry(src, dst *Chain, packets, acks int) {
+	src.Log(fmt.Sprintf("★ Relayed %d failed packets and %d failed acknowledgements: [%s]port{%s}<->[%s]port{%s}",
+		packets, acks, src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID))
+}
+
 func logChannelStates(src, dst *Chain, srcChan, dstChan *chantypes.QueryChannelResponse) {
 	src.Log(fmt.Sprintf("- [%s]@{%d}chan(%s)-{%s} : [%s]@{%d}chan(%s)-{%s}",
 		src.ChainID,
@@ -1,6 +1,7 @@
 package relayer
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 
@@ -20,7 +21,7 @@ var (
 // against the associated light client. If the headers do not match, the emitted
 // header and a reconstructed header are used in misbehaviour submission to
 // the IBC client on the source chain.
-func checkAndSubmitMisbehaviour(src, counterparty *Chain, events map[string][]string) error {
+func checkAndSubmitMisbehaviour(ctx context.Context, src, counterparty *Chain, events map[string][]string) error {
 	hdrs, ok := events[fmt.Sprintf("%s.%s", updateCliTag, headerTag)]
 	if !ok {
 		return nil
@@ -65,14 +66,14 @@ func checkAndSubmitMisbehaviour(src, counterparty *Chain, events map[string][]st
 		trustedHeader.TrustedHeight = emittedHeader.TrustedHeight
 
 		misbehaviour := tmclient.NewMisbehaviour(emittedClientID, emittedHeader, trustedHeader)
-		msg, err := clienttypes.NewMsgSubmitMisbehaviour(emittedClientID, misbehaviour, src.MustGetAddress())
+		msg, err := clienttypes.NewMsgSubmitMisbehaviour(emittedClientID, misbehaviour, src.MsgSigner())
 		if err != nil {
 			return err
 		}
 		if err := msg.ValidateBasic(); err != nil {
 			return err
 		}
-		res, success, err := src.SendMsg(msg)
+		res, success, err := src.SendMsg(ctx, msg)
 		if err != nil {
 			return err
 		}
@@ -11,14 +11,14 @@ import (
 	tmclient "github.com/cosmos/ibc-go/v2/modules/light-clients/07-tendermint/types"
 )
 
-// NOTE: we explicitly call 'MustGetAddress' before 'NewMsg...'
-// to ensure the correct config file is being used to generate
-// the account prefix. 'NewMsg...' functions take an AccAddress
+// NOTE: we explicitly call 'MsgSigner' or 'MustGetAddress' before
+// 'NewMsg...' to ensure the correct config file is being used to
+// generate the account prefix. 'NewMsg...' functions take an AccAddress
 // rather than a string. The 'address.String()' function uses
 // the currently set config file. Querying a counterparty would
-// swap the config file. 'MustGetAddress' sets the config file
-// correctly. Do not change this ordering until the SDK config
-// file handling has been refactored.
+// swap the config file. 'MsgSigner' and 'MustGetAddress' set the
+// config file correctly. Do not change this ordering until the SDK
+// config file handling has been refactored.
 // https://github.com/cosmos/cosmos-sdk/issues/8332
 
 // CreateClient creates an sdk.Msg to update the client on src with consensus state from dst
@@ -55,7 +55,7 @@ func (c *Chain) UpdateClient(dst *Chain, dsth *tmclient.Header) (sdk.Msg, error)
 	msg, err := clienttypes.NewMsgUpdateClient(
 		c.PathEnd.ClientID,
 		dsth,
-		c.MustGetAddress(), // 'MustGetAddress' must be called directly before calling 'NewMsg...'
+		c.MsgSigner(), // 'MsgSigner' must be called directly before calling 'NewMsg...'
 	)
 	if err != nil {
 		return nil, err
@@ -355,7 +355,7 @@ func (c *Chain) MsgRelayRecvPacket(counterparty *Chain, counterpartyHeight int64
 			),
 			comRes.Proof,
 			comRes.ProofHeight,
-			c.MustGetAddress(),
+			c.MsgSigner(),
 		), nil
 	}
 }
@@ -386,7 +386,7 @@ func (c *Chain) MsgRelayAcknowledgement(counterparty *Chain, counterpartyHeight
 			packet.ack,
 			ackRes.Proof,
 			ackRes.ProofHeight,
-			c.MustGetAddress()), nil
+			c.MsgSigner()), nil
 	}
 }
 
@@ -394,30 +394,93 @@ func (c *Chain) MsgRelayAcknowledgement(counterparty *Chain, counterpartyHeight
 // The counterparty represents the receiving chain where the receipts would have been
 // stored.
 func (c *Chain) MsgRelayTimeout(counterparty *Chain, counterpartyHeight int64, packet *relayMsgTimeout) (sdk.Msg, error) {
-	recvRes, err := counterparty.QueryPacketReceipt(counterpartyHeight, packet.seq)
-	switch {
-	case err != nil:
+	nextSeqRecv, proof, proofHeight, err := c.unreceivedProof(counterparty, counterpartyHeight, packet.seq)
+	if err != nil {
 		return nil, err
-	case recvRes.Proof == nil:
-		return nil, fmt.Errorf("timeout packet receipt proof seq(%d) is nil", packet.seq)
-	case recvRes == nil:
-		return nil, fmt.Errorf("timeout packet [%s]seq{%d} has no associated proofs", c.ChainID, packet.seq)
-	default:
-		return chantypes.NewMsgTimeout(
-			chantypes.NewPacket(
-				packet.packetData,
-				packet.seq,
-				c.PathEnd.PortID,
-				c.PathEnd.ChannelID,
-				counterparty.PathEnd.PortID,
-				counterparty.PathEnd.ChannelID,
-				packet.timeout,
-				packet.timeoutStamp,
-			),
+	}
+
+	return chantypes.NewMsgTimeout(
+		chantypes.NewPacket(
+			packet.packetData,
 			packet.seq,
-			recvRes.Proof,
-			recvRes.ProofHeight,
-			c.MustGetAddress(),
-		), nil
+			c.PathEnd.PortID,
+			c.PathEnd.ChannelID,
+			counterparty.PathEnd.PortID,
+			counterparty.PathEnd.ChannelID,
+			packet.timeout,
+			packet.timeoutStamp,
+		),
+		nextSeqRecv,
+		proof,
+		proofHeight,
+		c.MsgSigner(),
+	), nil
+}
+
+// MsgRelayTimeoutOnClose constructs the MsgTimeoutOnClose which is to be sent to the sending
+// chain once the counterparty's channel end is closed. The counterparty represents the receiving
+// chain where the receipts would have been stored.
+func (c *Chain) MsgRelayTimeoutOnClose(counterparty *Chain, counterpartyHeight int64,
+	packet *relayMsgTimeout) (sdk.Msg, error) {
+	// both proofs are queried at the same height so they share a proof height
+	nextSeqRecv, proof, proofHeight, err := c.unreceivedProof(counterparty, counterpartyHeight, packet.seq)
+	if err != nil {
+		return nil, err
+	}
+	chanRes, err := counterparty.QueryChannel(counterpartyHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case chanRes.Channel.State != chantypes.CLOSED:
+		return nil, fmt.Errorf("timeout on close packet [%s]seq{%d} counterparty channel is %s",
+			c.ChainID, packet.seq, chanRes.Channel.State)
+	case len(chanRes.Proof) == 0:
+		return nil, fmt.Errorf("timeout on close packet [%s]seq{%d} has no channel proof", c.ChainID, packet.seq)
+	}
+
+	return chantypes.NewMsgTimeoutOnClose(
+		chantypes.NewPacket(
+			packet.packetData,
+			packet.seq,
+			c.PathEnd.PortID,
+			c.PathEnd.ChannelID,
+			counterparty.PathEnd.PortID,
+			counterparty.PathEnd.ChannelID,
+			packet.timeout,
+			packet.timeoutStamp,
+		),
+		nextSeqRecv,
+		proof,
+		chanRes.Proof,
+		proofHeight,
+		c.MsgSigner(),
+	), nil
+}
+
+// unreceivedProof returns the proof that the packet with the given sequence was not received
+// by the counterparty along with the next sequence the counterparty expects. Ordered channels
+// prove the next receive sequence, unordered channels prove the absence of a packet receipt.
+func (c *Chain) unreceivedProof(counterparty *Chain, counterpartyHeight int64,
+	seq uint64) (nextSeqRecv uint64, proof []byte, proofHeight clienttypes.Height, err error) {
+	if c.PathEnd.GetOrder() == chantypes.ORDERED {
+		recvRes, err := counterparty.QueryNextSeqRecv(counterpartyHeight)
+		switch {
+		case err != nil:
+			return 0, nil, clienttypes.Height{}, err
+		case recvRes == nil || recvRes.Proof == nil:
+			return 0, nil, clienttypes.Height{}, fmt.Errorf("timeout packet next sequence receive proof seq(%d) is nil", seq)
+		}
+		return recvRes.NextSequenceReceive, recvRes.Proof, recvRes.ProofHeight, nil
+	}
+
+	recvRes, err := counterparty.QueryPacketReceipt(counterpartyHeight, seq)
+	switch {
+	case err != nil:
+		return 0, nil, clienttypes.Height{}, err
+	case recvRes == nil || recvRes.Proof == nil:
+		return 0, nil, clienttypes.Height{}, fmt.Errorf("timeout packet receipt proof seq(%d) is nil", seq)
 	}
+	return seq, recvRes.Proof, recvRes.ProofHeight, nil
 }
@@ -1,9 +1,15 @@
 package relayer
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
+	"sync"
+	"time"
 
 	retry "github.com/avast/retry-go"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -16,7 +22,8 @@ import (
 
 var (
 	// Ensure that NaiveStrategy satisfies the Strategy interface
-	_ Strategy = &NaiveStrategy{}
+	_ Strategy          = &NaiveStrategy{}
+	_ BlockTimeStrategy = &NaiveStrategy{}
 
 	// Strings for parsing events
 	spTag       = "send_packet"
@@ -30,8 +37,90 @@ var (
 	toHeightTag = "packet_timeout_height"
 	toTSTag     = "packet_timeout_timestamp"
 	seqTag      = "packet_sequence"
+
+	// Prefixes for keys of packets currently being relayed
+	packetKeyPrefix = "packet"
+	ackKeyPrefix    = "ack"
+)
+
+// Options of the naive strategy that can be set in a path's strategy config
+const (
+	NaiveOptionMaxTxSize    = "max-tx-size"    // maximum size of the msgs in a relay transaction in MB
+	NaiveOptionMaxMsgLength = "max-msg-length" // maximum number of msgs in a relay transaction
+	NaiveOptionOrdered      = "ordered"        // relay packets strictly in sequence order
+)
+
+// Defaults of the naive strategy options
+const (
+	DefaultMaxTxSize    = 2 * 1024 * 1024 // in bytes
+	DefaultMaxMsgLength = 5
 )
 
+func init() {
+	RegisterStrategy((&NaiveStrategy{}).GetType(), newNaiveStrategy)
+}
+
+// newNaiveStrategy builds a NaiveStrategy from the options of a path's strategy config
+func newNaiveStrategy(options map[string]interface{}) (Strategy, error) {
+	nrs := &NaiveStrategy{MaxTxSize: DefaultMaxTxSize, MaxMsgLength: DefaultMaxMsgLength}
+
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var (
+			value = options[key]
+			err   error
+		)
+		switch key {
+		case NaiveOptionMaxTxSize:
+			var size uint64
+			if size, err = uintOption(value); err == nil {
+				nrs.MaxTxSize = size * 1024 * 1024
+			}
+		case NaiveOptionMaxMsgLength:
+			nrs.MaxMsgLength, err = uintOption(value)
+		case NaiveOptionOrdered:
+			var ok bool
+			if nrs.Ordered, ok = value.(bool); !ok {
+				err = fmt.Errorf("must be true or false, got %v", value)
+			}
+		default:
+			return nil, fmt.Errorf("unknown option %s of the %s strategy, valid options are %s, %s and %s",
+				key, nrs.GetType(), NaiveOptionMaxTxSize, NaiveOptionMaxMsgLength, NaiveOptionOrdered)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid option %s of the %s strategy: %w", key, nrs.GetType(), err)
+		}
+	}
+	return nrs, nil
+}
+
+// uintOption returns the value of a strategy option that must be a non-negative integer, as
+// decoded from a YAML or JSON config
+func uintOption(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case int:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case int64:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	case uint64:
+		return v, nil
+	case float64:
+		if v >= 0 && v == math.Trunc(v) {
+			return uint64(v), nil
+		}
+	}
+	return 0, fmt.Errorf("must be a non-negative integer, got %v", value)
+}
+
 // NewNaiveStrategy returns the proper config for the NaiveStrategy
 func NewNaiveStrategy() *StrategyCfg {
 	return &StrategyCfg{
@@ -41,9 +130,62 @@ func NewNaiveStrategy() *StrategyCfg {
 
 // NaiveStrategy is an implementation of Strategy.
 type NaiveStrategy struct {
-	Ordered      bool
-	MaxTxSize    uint64 // maximum permitted size of the msgs in a bundled relay transaction
-	MaxMsgLength uint64 // maximum amount of messages in a bundled relay transaction
+	Ordered      bool          // relay packets strictly in sequence order, as required by ORDERED channels
+	MaxTxSize    uint64        // maximum permitted size of the msgs in a bundled relay transaction
+	MaxMsgLength uint64        // maximum amount of messages in a bundled relay transaction
+	Filter       *PacketFilter // packets that don't pass the filter are not relayed
+
+	// FailedPackets stores packets from the event listener that failed to relay so they
+	// can be retried later, failures are only logged if it is nil
+	FailedPackets *FailedPacketQueue
+
+	// pending holds the timeouts of packets seen in send_packet events that haven't been
+	// received yet, keyed by the chain, port and channel they were sent from
+	pendingMu sync.Mutex
+	pending   map[string]map[uint64]pendingPacket
+
+	// blockTimes holds the header time of the latest block of each chain, which timestamp
+	// timeouts of pending packets are checked against
+	blockTimes map[string]time.Time
+
+	// inFlight holds the keys of packets and acknowledgements that are currently being
+	// relayed so the event listener and the periodic sweep don't relay the same packet twice
+	inFlight sync.Map
+}
+
+// relayKey returns the key identifying a packet or acknowledgement sent from chain c
+func relayKey(prefix string, c *Chain, seq uint64) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%d", prefix, c.ChainID, c.PathEnd.PortID, c.PathEnd.ChannelID, seq)
+}
+
+// claim marks the key as being relayed and returns false if it already was
+func (nrs *NaiveStrategy) claim(key string) bool {
+	_, loaded := nrs.inFlight.LoadOrStore(key, struct{}{})
+	return !loaded
+}
+
+// release removes the keys from the set of packets being relayed
+func (nrs *NaiveStrategy) release(keys []string) {
+	for _, key := range keys {
+		nrs.inFlight.Delete(key)
+	}
+}
+
+// claimSequences returns the subset of seqs sent from chain c that are not already being
+// relayed along with their keys, which the caller must release once done
+func (nrs *NaiveStrategy) claimSequences(prefix string, c *Chain, seqs []uint64) ([]uint64, []string) {
+	var (
+		out  = []uint64{}
+		keys = []string{}
+	)
+	for _, seq := range seqs {
+		key := relayKey(prefix, c, seq)
+		if nrs.claim(key) {
+			out = append(out, seq)
+			keys = append(keys, key)
+		}
+	}
+	return out, keys
 }
 
 // GetType implements Strategy
@@ -51,8 +193,65 @@ func (nrs *NaiveStrategy) GetType() string {
 	return "naive"
 }
 
+// SetPacketFilter implements FilteredStrategy
+func (nrs *NaiveStrategy) SetPacketFilter(filter *PacketFilter) {
+	nrs.Filter = filter
+}
+
+// SetFailedPacketQueue implements DeadLetterStrategy
+func (nrs *NaiveStrategy) SetFailedPacketQueue(q *FailedPacketQueue) {
+	nrs.FailedPackets = q
+}
+
+// GetFailedPacketQueue implements DeadLetterStrategy
+func (nrs *NaiveStrategy) GetFailedPacketQueue() *FailedPacketQueue {
+	return nrs.FailedPackets
+}
+
+// filterSequences returns the subset of seqs sent from chain c whose packets pass the filter.
+// Packets whose data can't be queried are skipped and picked up by a later sweep.
+func (nrs *NaiveStrategy) filterSequences(c *Chain, height uint64, seqs []uint64) []uint64 {
+	if nrs.Filter.Empty() {
+		return seqs
+	}
+
+	out := []uint64{}
+	for _, seq := range seqs {
+		rp, err := sentPacketFromSequence(c, height, seq)
+		if err == nil {
+			err = nrs.Filter.Check(rp.packetData)
+		}
+		if err != nil {
+			c.logPacketSkipped(seq, err)
+			continue
+		}
+		out = append(out, seq)
+	}
+	return out
+}
+
+// filterEventPackets returns the packets from the event listener that pass the filter.
+// Acknowledgements are always relayed since their packets already passed the filter.
+func (nrs *NaiveStrategy) filterEventPackets(dst *Chain, rlyPackets []relayPacket) []relayPacket {
+	if nrs.Filter.Empty() {
+		return rlyPackets
+	}
+
+	var out []relayPacket
+	for _, rp := range rlyPackets {
+		if recv, ok := rp.(*relayMsgRecvPacket); ok {
+			if err := nrs.Filter.Check(recv.packetData); err != nil {
+				dst.logPacketSkipped(recv.seq, err)
+				continue
+			}
+		}
+		out = append(out, rp)
+	}
+	return out
+}
+
 // UnrelayedSequences returns the unrelayed sequence numbers between two chains
-func (nrs *NaiveStrategy) UnrelayedSequences(src, dst *Chain) (*RelaySequences, error) {
+func (nrs *NaiveStrategy) UnrelayedSequences(ctx context.Context, src, dst *Chain) (*RelaySequences, error) {
 	var (
 		eg           = new(errgroup.Group)
 		srcPacketSeq = []uint64{}
@@ -60,6 +259,10 @@ func (nrs *NaiveStrategy) UnrelayedSequences(src, dst *Chain) (*RelaySequences,
 		rs           = &RelaySequences{Src: []uint64{}, Dst: []uint64{}}
 	)
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	srch, dsth, err := QueryLatestHeights(src, dst)
 	if err != nil {
 		return nil, err
@@ -140,11 +343,15 @@ func (nrs *NaiveStrategy) UnrelayedSequences(src, dst *Chain) (*RelaySequences,
 		return nil, err
 	}
 
+	// drop any packets that don't pass the path's packet filter
+	rs.Src = nrs.filterSequences(src, uint64(srch), rs.Src)
+	rs.Dst = nrs.filterSequences(dst, uint64(dsth), rs.Dst)
+
 	return rs, nil
 }
 
 // UnrelayedAcknowledgements returns the unrelayed sequence numbers between two chains
-func (nrs *NaiveStrategy) UnrelayedAcknowledgements(src, dst *Chain) (*RelaySequences, error) {
+func (nrs *NaiveStrategy) UnrelayedAcknowledgements(ctx context.Context, src, dst *Chain) (*RelaySequences, error) {
 	var (
 		eg           = new(errgroup.Group)
 		srcPacketSeq = []uint64{}
@@ -152,6 +359,10 @@ func (nrs *NaiveStrategy) UnrelayedAcknowledgements(src, dst *Chain) (*RelaySequ
 		rs           = &RelaySequences{Src: []uint64{}, Dst: []uint64{}}
 	)
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	srch, dsth, err := QueryLatestHeights(src, dst)
 	if err != nil {
 		return nil, err
@@ -244,34 +455,129 @@ func (nrs *NaiveStrategy) UnrelayedAcknowledgements(src, dst *Chain) (*RelaySequ
 }
 
 // HandleEvents defines how the relayer will handle block and transaction events as they are emitted
-func (nrs *NaiveStrategy) HandleEvents(src, dst *Chain, srch, dsth int64, events map[string][]string) {
+func (nrs *NaiveStrategy) HandleEvents(ctx context.Context, src, dst *Chain, srch, dsth int64,
+	events map[string][]string) {
 	// check for misbehaviour and submit if found
 	// events came from dst chain, use that chain as the source
 	// the chain messages are submitted to
-	if err := checkAndSubmitMisbehaviour(dst, src, events); err != nil {
+	if err := checkAndSubmitMisbehaviour(ctx, dst, src, events); err != nil {
 		src.Error(err)
 	}
 
+	// send timeouts for any tracked packets sent from dst that have expired on src
+	nrs.relayExpiredPackets(ctx, src, dst, srch)
+
 	rlyPackets, err := relayPacketsFromEventListener(src.PathEnd, dst.PathEnd, events)
 	if len(rlyPackets) > 0 && err == nil {
-		// TODO: handle errors here by retrying the whole thing. Maybe try
-		// updating the heights on the retry?
-		retry.Do(func() error { return nrs.sendTxFromEventPackets(src, dst, srch, dsth, rlyPackets) }, retry.OnRetry(func(n uint, err error) {
+		// skip any packets that don't pass the path's packet filter
+		if rlyPackets = nrs.filterEventPackets(dst, rlyPackets); len(rlyPackets) == 0 {
+			return
+		}
+
+		// on ordered channels only relay the packets src can receive next, the rest are
+		// picked up by the sweep once the gap before them is filled
+		if rlyPackets, err = nrs.filterOrderedEventPackets(src, dst, srch, rlyPackets); err != nil {
+			src.Error(err)
+			return
+		}
+		if len(rlyPackets) == 0 {
+			return
+		}
+
+		// track the packets' timeouts until they are received
+		nrs.trackPending(dst, rlyPackets)
+
+		// skip any packets that are already being relayed by the sweep
+		var keys []string
+		rlyPackets, keys = nrs.claimEventPackets(src, dst, rlyPackets)
+		defer nrs.release(keys)
+		if len(rlyPackets) == 0 {
+			return
+		}
+
+		// packets that still fail after retrying are queued to be retried later
+		if err := retry.Do(func() error {
+			if err := ctx.Err(); err != nil {
+				return retry.Unrecoverable(err)
+			}
+			err := nrs.sendTxFromEventPackets(ctx, src, dst, srch, dsth, rlyPackets)
+			// a batch that wasn't committed may still be in a mempool, so it is queued right
+			// away instead of being resent
+			if errors.Is(err, ErrTxNotCommitted) {
+				return retry.Unrecoverable(err)
+			}
+			return err
+		}, retry.OnRetry(func(n uint, err error) {
 			err = nil
 			srch, dsth, err = QueryLatestHeights(src, dst)
 			return
-		}))
+		})); err != nil {
+			nrs.recordFailedEventPackets(src, dst, rlyPackets, err)
+			return
+		}
+		nrs.untrackPending(dst, rlyPackets)
+	}
+}
+
+// recordFailedEventPackets adds packets from the event listener that failed to relay to the
+// failed packet queue. The packets were sent from dst and the acknowledgements written on dst.
+func (nrs *NaiveStrategy) recordFailedEventPackets(src, dst *Chain, rlyPackets []relayPacket, relayErr error) {
+	var packets, acks []uint64
+	for _, rp := range rlyPackets {
+		switch rp.(type) {
+		case *relayMsgPacketAck:
+			acks = append(acks, rp.Seq())
+		default:
+			packets = append(packets, rp.Seq())
+		}
+	}
+
+	if nrs.FailedPackets == nil {
+		src.Error(fmt.Errorf("failed to relay packets%v and acknowledgements%v from %s: %w",
+			packets, acks, dst.ChainID, relayErr))
+		return
+	}
+
+	src.Error(fmt.Errorf("failed to relay packets%v and acknowledgements%v from %s, queued for retry: %w",
+		packets, acks, dst.ChainID, relayErr))
+	if err := nrs.FailedPackets.Failed(FailedPacketKindPacket, dst, src, packets, relayErr); err != nil {
+		src.Error(err)
+	}
+	if err := nrs.FailedPackets.Failed(FailedPacketKindAck, dst, src, acks, relayErr); err != nil {
+		src.Error(err)
+	}
+}
 
+// claimEventPackets returns the packets from the event listener that are not already being
+// relayed along with their keys. Packets are sent from dst and acknowledgements written on dst
+// for packets sent from src.
+func (nrs *NaiveStrategy) claimEventPackets(src, dst *Chain, rlyPackets []relayPacket) ([]relayPacket, []string) {
+	var (
+		out  []relayPacket
+		keys []string
+	)
+	for _, rp := range rlyPackets {
+		var key string
+		switch rp.(type) {
+		case *relayMsgPacketAck:
+			key = relayKey(ackKeyPrefix, src, rp.Seq())
+		default:
+			key = relayKey(packetKeyPrefix, dst, rp.Seq())
+		}
+		if nrs.claim(key) {
+			out = append(out, rp)
+			keys = append(keys, key)
+		}
 	}
+	return out, keys
 }
 
 func relayPacketsFromEventListener(src, dst *PathEnd, events map[string][]string) (rlyPkts []relayPacket, err error) {
 	// check for send packets
 	if pdval, ok := events[fmt.Sprintf("%s.%s", spTag, dataTag)]; ok {
 		for i, pd := range pdval {
-			// Ensure that we only relay over the channel and port specified
-			// OPTIONAL FEATURE: add additional filtering options
-			// Example Events - "transfer.amount(sdk.Coin)", "message.sender(sdk.AccAddress)"
+			// Ensure that we only relay over the channel and port specified,
+			// the path's packet filter is applied by the strategy
 			srcChan, srcPort := events[fmt.Sprintf("%s.%s", spTag, srcChanTag)], events[fmt.Sprintf("%s.%s", spTag, srcPortTag)]
 			dstChan, dstPort := events[fmt.Sprintf("%s.%s", spTag, dstChanTag)], events[fmt.Sprintf("%s.%s", spTag, dstPortTag)]
 
@@ -317,7 +623,6 @@ func relayPacketsFromEventListener(src, dst *PathEnd, events map[string][]string
 	if pdval, ok := events[fmt.Sprintf("%s.%s", waTag, dataTag)]; ok {
 		for i, pd := range pdval {
 			// Ensure that we only relay over the channel and port specified
-			// OPTIONAL FEATURE: add additional filtering options
 			srcChan, srcPort := events[fmt.Sprintf("%s.%s", waTag, srcChanTag)], events[fmt.Sprintf("%s.%s", waTag, srcPortTag)]
 			dstChan, dstPort := events[fmt.Sprintf("%s.%s", waTag, dstChanTag)], events[fmt.Sprintf("%s.%s", waTag, dstPortTag)]
 
@@ -365,7 +670,11 @@ func relayPacketsFromEventListener(src, dst *PathEnd, events map[string][]string
 	return rlyPkts, nil
 }
 
-func (nrs *NaiveStrategy) sendTxFromEventPackets(src, dst *Chain, srch, dsth int64, rlyPackets []relayPacket) error {
+func (nrs *NaiveStrategy) sendTxFromEventPackets(ctx context.Context, src, dst *Chain, srch, dsth int64,
+	rlyPackets []relayPacket) error {
+	// sign with the next keys of the chains' key pools
+	src, dst = src.nextSigner(), dst.nextSigner()
+
 	// send the transaction, retrying if not successful
 
 	dstHeader, err := dst.GetIBCUpdateHeader(src, dsth)
@@ -397,8 +706,8 @@ func (nrs *NaiveStrategy) sendTxFromEventPackets(src, dst *Chain, srch, dsth int
 		txs.Src = append(txs.Src, msg)
 	}
 
-	if txs.Send(src, dst); !txs.Success() {
-		return fmt.Errorf("failed to send packets, see above logs for details")
+	if txs.Send(ctx, src, dst); !txs.Success() {
+		return txs.Err()
 	}
 
 	return nil
@@ -411,7 +720,17 @@ type RelaySequences struct {
 }
 
 // RelayAcknowledgements creates transactions to relay acknowledgements from src to dst and from dst to src
-func (nrs *NaiveStrategy) RelayAcknowledgements(src, dst *Chain, sp *RelaySequences) error {
+func (nrs *NaiveStrategy) RelayAcknowledgements(ctx context.Context, src, dst *Chain, sp *RelaySequences) error {
+	// sign with the next keys of the chains' key pools
+	src, dst = src.nextSigner(), dst.nextSigner()
+
+	// skip any acknowledgements that are already being relayed
+	srcSeqs, srcKeys := nrs.claimSequences(ackKeyPrefix, dst, sp.Src)
+	defer nrs.release(srcKeys)
+	dstSeqs, dstKeys := nrs.claimSequences(ackKeyPrefix, src, sp.Dst)
+	defer nrs.release(dstKeys)
+	sp = &RelaySequences{Src: srcSeqs, Dst: dstSeqs}
+
 	// set the maximum relay transaction constraints
 	msgs := &RelayMsgs{
 		Src:          []sdk.Msg{},
@@ -492,20 +811,36 @@ func (nrs *NaiveStrategy) RelayAcknowledgements(src, dst *Chain, sp *RelaySequen
 	}
 
 	// send messages to their respective chains
-	if msgs.Send(src, dst); msgs.Success() {
-		if len(msgs.Dst) > 1 {
-			dst.logPacketsRelayed(src, len(msgs.Dst)-1)
-		}
-		if len(msgs.Src) > 1 {
-			src.logPacketsRelayed(dst, len(msgs.Src)-1)
-		}
+	if msgs.Send(ctx, src, dst); !msgs.Success() {
+		return msgs.Err()
+	}
+
+	// an acknowledged packet was received, stop tracking its timeout on the chain that sent it
+	nrs.untrackSequences(dst, sp.Src)
+	nrs.untrackSequences(src, sp.Dst)
+
+	if len(msgs.Dst) > 1 {
+		dst.logPacketsRelayed(src, len(msgs.Dst)-1)
+	}
+	if len(msgs.Src) > 1 {
+		src.logPacketsRelayed(dst, len(msgs.Src)-1)
 	}
 
 	return nil
 }
 
 // RelayPackets creates transactions to relay packets from src to dst and from dst to src
-func (nrs *NaiveStrategy) RelayPackets(src, dst *Chain, sp *RelaySequences) error {
+func (nrs *NaiveStrategy) RelayPackets(ctx context.Context, src, dst *Chain, sp *RelaySequences) error {
+	// sign with the next keys of the chains' key pools
+	src, dst = src.nextSigner(), dst.nextSigner()
+
+	// skip any packets that are already being relayed
+	srcSeqs, srcKeys := nrs.claimSequences(packetKeyPrefix, src, sp.Src)
+	defer nrs.release(srcKeys)
+	dstSeqs, dstKeys := nrs.claimSequences(packetKeyPrefix, dst, sp.Dst)
+	defer nrs.release(dstKeys)
+	sp = &RelaySequences{Src: srcSeqs, Dst: dstSeqs}
+
 	// set the maximum relay transaction constraints
 	msgs := &RelayMsgs{
 		Src:          []sdk.Msg{},
@@ -519,27 +854,71 @@ func (nrs *NaiveStrategy) RelayPackets(src, dst *Chain, sp *RelaySequences) erro
 		return err
 	}
 
+	// packets sent towards a closed channel end are timed out on close
+	srcChan, dstChan, err := QueryChannelPair(src, dst, srch, dsth)
+	if err != nil {
+		return err
+	}
+	srcClosed := srcChan.Channel.State == chantypes.CLOSED
+	dstClosed := dstChan.Channel.State == chantypes.CLOSED
+
+	// on ordered channels only relay from the next sequence the receiving chain expects up to
+	// the first gap, a packet out of order would fail the whole batch
+	ordered := nrs.ordered(src)
+	if ordered {
+		if sp.Src, err = orderedSequences(dst, dsth, sp.Src); err != nil {
+			return err
+		}
+		if sp.Dst, err = orderedSequences(src, srch, sp.Dst); err != nil {
+			return err
+		}
+		msgs.Ordered = true
+	}
+
+	// on ordered channels a packet that can't be relayed stops the relay of every later packet
+	// in the same direction, the packets relayed before it are still sent
+	var (
+		srcErr, dstErr         error
+		srcRelayed, dstRelayed []uint64
+	)
+
 	// add messages for sequences on src
 	for _, seq := range sp.Src {
 		// Query src for the sequence number to get type of packet
 		var recvMsg, timeoutMsg sdk.Msg
 		if err = retry.Do(func() error {
-			recvMsg, timeoutMsg, err = relayPacketFromSequence(src, dst, uint64(srch), uint64(dsth), seq)
+			if err := ctx.Err(); err != nil {
+				return retry.Unrecoverable(err)
+			}
+			recvMsg, timeoutMsg, err = relayPacketFromSequence(src, dst, uint64(srch), uint64(dsth), seq, dstClosed)
 			return err
 		}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
 			srch, dsth, _ = QueryLatestHeights(src, dst)
 		})); err != nil {
-			return err
+			if !ordered {
+				return err
+			}
+			srcErr = fmt.Errorf("failed to relay packet [%s]seq{%d}, later packets were not relayed: %w",
+				src.ChainID, seq, err)
+			break
 		}
 
 		// depending on the type of message to be relayed, we need to
 		// send to different chains
+		if recvMsg != nil || timeoutMsg != nil {
+			srcRelayed = append(srcRelayed, seq)
+		}
 		if recvMsg != nil {
 			msgs.Dst = append(msgs.Dst, recvMsg)
 		}
 
 		if timeoutMsg != nil {
 			msgs.Src = append(msgs.Src, timeoutMsg)
+
+			// a timeout closes an ordered channel, no later packet can be received
+			if ordered {
+				break
+			}
 		}
 	}
 
@@ -548,26 +927,45 @@ func (nrs *NaiveStrategy) RelayPackets(src, dst *Chain, sp *RelaySequences) erro
 		// Query dst for the sequence number to get type of packet
 		var recvMsg, timeoutMsg sdk.Msg
 		if err = retry.Do(func() error {
-			recvMsg, timeoutMsg, err = relayPacketFromSequence(dst, src, uint64(dsth), uint64(srch), seq)
-			return nil
+			if err := ctx.Err(); err != nil {
+				return retry.Unrecoverable(err)
+			}
+			recvMsg, timeoutMsg, err = relayPacketFromSequence(dst, src, uint64(dsth), uint64(srch), seq, srcClosed)
+			return err
 		}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
 			srch, dsth, _ = QueryLatestHeights(src, dst)
 		})); err != nil {
-			return err
+			if !ordered {
+				return err
+			}
+			dstErr = fmt.Errorf("failed to relay packet [%s]seq{%d}, later packets were not relayed: %w",
+				dst.ChainID, seq, err)
+			break
 		}
 
 		// depending on the type of message to be relayed, we need to
 		// send to different chains
+		if recvMsg != nil || timeoutMsg != nil {
+			dstRelayed = append(dstRelayed, seq)
+		}
 		if recvMsg != nil {
 			msgs.Src = append(msgs.Src, recvMsg)
 		}
 
 		if timeoutMsg != nil {
 			msgs.Dst = append(msgs.Dst, timeoutMsg)
+
+			// a timeout closes an ordered channel, no later packet can be received
+			if ordered {
+				break
+			}
 		}
 	}
 
 	if !msgs.Ready() {
+		if err = firstError(srcErr, dstErr); err != nil {
+			return err
+		}
 		src.Log(fmt.Sprintf("- No packets to relay between [%s]port{%s} and [%s]port{%s}",
 			src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID))
 		return nil
@@ -601,23 +999,123 @@ func (nrs *NaiveStrategy) RelayPackets(src, dst *Chain, sp *RelaySequences) erro
 	}
 
 	// send messages to their respective chains
-	if msgs.Send(src, dst); msgs.Success() {
-		if len(msgs.Dst) > 1 {
-			dst.logPacketsRelayed(src, len(msgs.Dst)-1)
+	if msgs.Send(ctx, src, dst); !msgs.Success() {
+		return msgs.Err()
+	}
+
+	// the relayed packets were received or timed out, stop tracking their timeouts
+	nrs.untrackSequences(src, srcRelayed)
+	nrs.untrackSequences(dst, dstRelayed)
+
+	if len(msgs.Dst) > 1 {
+		dst.logPacketsRelayed(src, len(msgs.Dst)-1)
+	}
+	if len(msgs.Src) > 1 {
+		src.logPacketsRelayed(dst, len(msgs.Src)-1)
+	}
+
+	return firstError(srcErr, dstErr)
+}
+
+// ordered returns true if packets sent from src must be relayed in sequence order
+func (nrs *NaiveStrategy) ordered(src *Chain) bool {
+	return nrs.Ordered || src.PathEnd.GetOrder() == chantypes.ORDERED
+}
+
+// orderedSequences returns the sequences that can be relayed to dst on an ordered channel, in
+// order: starting from the next sequence dst expects to receive and stopping at the first gap.
+// Sequences dst has already received are dropped.
+func orderedSequences(dst *Chain, dsth int64, seqs []uint64) ([]uint64, error) {
+	if len(seqs) == 0 {
+		return seqs, nil
+	}
+
+	res, err := dst.QueryNextSeqRecv(dsth)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]uint64, len(seqs))
+	copy(sorted, seqs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var (
+		out  = []uint64{}
+		next = res.NextSequenceReceive
+	)
+	for _, seq := range sorted {
+		switch {
+		case seq < next:
+			continue
+		case seq > next:
+			dst.logPacketGap(next, seq)
+			return out, nil
+		}
+		out = append(out, seq)
+		next++
+	}
+	return out, nil
+}
+
+// filterOrderedEventPackets drops the packets from the event listener that can't be received by
+// src yet on an ordered channel. The packets were sent from dst, acknowledgements are kept.
+func (nrs *NaiveStrategy) filterOrderedEventPackets(src, dst *Chain, srch int64,
+	rlyPackets []relayPacket) ([]relayPacket, error) {
+	if !nrs.ordered(dst) {
+		return rlyPackets, nil
+	}
+
+	var seqs []uint64
+	for _, rp := range rlyPackets {
+		if _, ok := rp.(*relayMsgRecvPacket); ok {
+			seqs = append(seqs, rp.Seq())
 		}
-		if len(msgs.Src) > 1 {
-			src.logPacketsRelayed(dst, len(msgs.Src)-1)
+	}
+	if len(seqs) == 0 {
+		return rlyPackets, nil
+	}
+
+	seqs, err := orderedSequences(src, srch, seqs)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []relayPacket
+	for _, rp := range rlyPackets {
+		if _, ok := rp.(*relayMsgRecvPacket); !ok || containsSeq(seqs, rp.Seq()) {
+			out = append(out, rp)
 		}
-	} else {
-		fmt.Println()
 	}
+	return out, nil
+}
 
+// firstError returns the first non-nil error
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // relayPacketFromSequence relays a packet with a given seq on src
-// and returns recvPacket msgs, timeoutPacketmsgs and error
-func relayPacketFromSequence(src, dst *Chain, srch, dsth, seq uint64) (sdk.Msg, sdk.Msg, error) {
+// and returns recvPacket msgs, timeoutPacketmsgs and error. If dstClosed is set
+// the packet can no longer be received and a MsgTimeoutOnClose is returned.
+func relayPacketFromSequence(src, dst *Chain, srch, dsth, seq uint64, dstClosed bool) (sdk.Msg, sdk.Msg, error) {
+	if dstClosed {
+		pkt, err := sentPacketFromSequence(src, srch, seq)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		timeout, err := src.MsgRelayTimeoutOnClose(dst, int64(dsth), pkt.timeoutPacket())
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, timeout, nil
+	}
+
 	// var packet, timeout sdk.Msg
 	txs, err := src.QueryTxs(uint64(srch), 1, 1000, rcvPacketQuery(src.PathEnd.ChannelID, int(seq)))
 	switch {
@@ -669,6 +1167,65 @@ func relayPacketFromSequence(src, dst *Chain, srch, dsth, seq uint64) (sdk.Msg,
 	return nil, nil, fmt.Errorf("should have errored before here")
 }
 
+// sentPacketFromSequence returns the packet with the given sequence sent by src
+func sentPacketFromSequence(src *Chain, srch, seq uint64) (*relayMsgRecvPacket, error) {
+	txs, err := src.QueryTxs(srch, 1, 1000, rcvPacketQuery(src.PathEnd.ChannelID, int(seq)))
+	switch {
+	case err != nil:
+		return nil, err
+	case len(txs.Txs) == 0:
+		return nil, fmt.Errorf("no transactions returned with query")
+	}
+
+	for _, tx := range txs.Txs {
+		for _, e := range tx.TxResult.Events {
+			if e.Type != spTag {
+				continue
+			}
+
+			var (
+				rp      = &relayMsgRecvPacket{seq: seq}
+				matches = 0
+			)
+			for _, p := range e.Attributes {
+				switch string(p.Key) {
+				case srcChanTag:
+					if string(p.Value) == src.PathEnd.ChannelID {
+						matches++
+					}
+				case srcPortTag:
+					if string(p.Value) == src.PathEnd.PortID {
+						matches++
+					}
+				case seqTag:
+					if string(p.Value) == strconv.FormatUint(seq, 10) {
+						matches++
+					}
+				case dataTag:
+					rp.packetData = p.Value
+				case toHeightTag:
+					timeout, err := clienttypes.ParseHeight(string(p.Value))
+					if err != nil {
+						return nil, err
+					}
+					rp.timeout = timeout
+				case toTSTag:
+					timeout, err := strconv.ParseUint(string(p.Value), 10, 64)
+					if err != nil {
+						return nil, err
+					}
+					rp.timeoutStamp = timeout
+				}
+			}
+			if matches == 3 {
+				return rp, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no packet with sequence %d found in tx query", seq)
+}
+
 // source is the sending chain, destination is the receiving chain
 func acknowledgementFromSequence(src, dst *Chain, dsth, seq uint64) (sdk.Msg, error) {
 	txs, err := dst.QueryTxs(uint64(dsth), 1, 1000, ackPacketQuery(dst.PathEnd.ChannelID, int(seq)))
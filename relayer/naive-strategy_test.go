@@ -0,0 +1,47 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNaiveStrategy(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		valid   bool
+
+		maxTxSize, maxMsgLength uint64
+		ordered                 bool
+	}{
+		{"defaults", nil, true, DefaultMaxTxSize, DefaultMaxMsgLength, false},
+		{"yaml options", map[string]interface{}{"max-tx-size": 4, "max-msg-length": 10, "ordered": true},
+			true, 4 * mb, 10, true},
+		{"json options", map[string]interface{}{"max-tx-size": float64(1), "max-msg-length": float64(3)},
+			true, 1 * mb, 3, false},
+		{"no limits", map[string]interface{}{"max-tx-size": 0, "max-msg-length": 0}, true, 0, 0, false},
+		{"size as string", map[string]interface{}{"max-tx-size": "2"}, false, 0, 0, false},
+		{"negative length", map[string]interface{}{"max-msg-length": -1}, false, 0, 0, false},
+		{"fractional length", map[string]interface{}{"max-msg-length": 2.5}, false, 0, 0, false},
+		{"ordered as string", map[string]interface{}{"ordered": "yes"}, false, 0, 0, false},
+		{"unknown option", map[string]interface{}{"max-msgs": 5}, false, 0, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			strategy, err := newNaiveStrategy(tc.options)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			nrs := strategy.(*NaiveStrategy)
+			require.Equal(t, tc.maxTxSize, nrs.MaxTxSize)
+			require.Equal(t, tc.maxMsgLength, nrs.MaxMsgLength)
+			require.Equal(t, tc.ordered, nrs.Ordered)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package relayer
 
 import (
 	"fmt"
+	"strings"
 
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
@@ -89,9 +90,10 @@ type PathAction struct {
 // Path represents a pair of chains and the identifiers needed to
 // relay over them
 type Path struct {
-	Src      *PathEnd     `yaml:"src" json:"src"`
-	Dst      *PathEnd     `yaml:"dst" json:"dst"`
-	Strategy *StrategyCfg `yaml:"strategy" json:"strategy"`
+	Src      *PathEnd      `yaml:"src" json:"src"`
+	Dst      *PathEnd      `yaml:"dst" json:"dst"`
+	Strategy *StrategyCfg  `yaml:"strategy" json:"strategy"`
+	Filter   *PacketFilter `yaml:"filter,omitempty" json:"filter,omitempty"`
 }
 
 // Ordered returns true if the path is ordered and false if otherwise
@@ -152,8 +154,9 @@ type PathStatus struct {
 
 // PathWithStatus is used for showing the status of the path
 type PathWithStatus struct {
-	Path   *Path      `yaml:"path" json:"chains"`
-	Status PathStatus `yaml:"status" json:"status"`
+	Path       *Path      `yaml:"path" json:"chains"`
+	Status     PathStatus `yaml:"status" json:"status"`
+	Strategies []string   `yaml:"registered-strategies" json:"registered-strategies"`
 }
 
 // QueryPathStatus returns an instance of the path struct with some attached data about
@@ -167,7 +170,11 @@ func (p *Path) QueryPathStatus(src, dst *Chain) *PathWithStatus {
 		srcConn, dstConn *conntypes.QueryConnectionResponse
 		srcChan, dstChan *chantypes.QueryChannelResponse
 
-		out = &PathWithStatus{Path: p, Status: PathStatus{false, false, false, false}}
+		out = &PathWithStatus{
+			Path:       p,
+			Status:     PathStatus{false, false, false, false},
+			Strategies: RegisteredStrategies(),
+		}
 	)
 	eg.Go(func() error {
 		srch, err = src.QueryLatestHeight()
@@ -249,11 +256,12 @@ func (ps *PathWithStatus) PrintString(name string) string {
     Chains:       %s
     Clients:      %s
     Connection:   %s
-    Channel:      %s`, name, pth.Strategy.Type, pth.Src.ChainID,
+    Channel:      %s
+  REGISTERED STRATEGIES: %s`, name, pth.Strategy.Type, pth.Src.ChainID,
 		pth.Src.ClientID, pth.Src.ConnectionID, pth.Src.ChannelID, pth.Src.PortID,
 		pth.Dst.ChainID, pth.Dst.ClientID, pth.Dst.ConnectionID, pth.Dst.ChannelID, pth.Dst.PortID,
 		checkmark(ps.Status.Chains), checkmark(ps.Status.Clients), checkmark(ps.Status.Connection),
-		checkmark(ps.Status.Channel))
+		checkmark(ps.Status.Channel), strings.Join(ps.Strategies, ", "))
 }
 
 func checkmark(status bool) string {
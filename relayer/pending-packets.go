@@ -0,0 +1,160 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+)
+
+// pendingPacket is a packet seen in a send_packet event that has not been received yet
+type pendingPacket struct {
+	seq          uint64
+	timeout      clienttypes.Height
+	timeoutStamp uint64
+}
+
+// expired returns true if the packet has timed out on the receiving chain, whose latest block
+// is at the given height and header time. Timestamp timeouts don't expire at a zero time.
+func (pp pendingPacket) expired(revision uint64, height int64, blockTime time.Time) bool {
+	if !pp.timeout.IsZero() && height > 0 &&
+		clienttypes.NewHeight(revision, uint64(height)).GTE(pp.timeout) {
+		return true
+	}
+	return pp.timeoutStamp != 0 && !blockTime.IsZero() && blockTime.UnixNano() >= int64(pp.timeoutStamp)
+}
+
+// pendingKey returns the key of the pending packets sent from chain c
+func pendingKey(c *Chain) string {
+	return fmt.Sprintf("%s/%s/%s", c.ChainID, c.PathEnd.PortID, c.PathEnd.ChannelID)
+}
+
+// trackPending starts tracking the timeouts of the given packets sent from chain c. Packets
+// without a timeout are ignored.
+func (nrs *NaiveStrategy) trackPending(c *Chain, rlyPackets []relayPacket) {
+	nrs.pendingMu.Lock()
+	defer nrs.pendingMu.Unlock()
+
+	for _, rp := range rlyPackets {
+		recv, ok := rp.(*relayMsgRecvPacket)
+		if !ok || (recv.timeout.IsZero() && recv.timeoutStamp == 0) {
+			continue
+		}
+
+		if nrs.pending == nil {
+			nrs.pending = make(map[string]map[uint64]pendingPacket)
+		}
+		key := pendingKey(c)
+		if nrs.pending[key] == nil {
+			nrs.pending[key] = make(map[uint64]pendingPacket)
+		}
+		nrs.pending[key][recv.seq] = pendingPacket{
+			seq:          recv.seq,
+			timeout:      recv.timeout,
+			timeoutStamp: recv.timeoutStamp,
+		}
+	}
+}
+
+// SetBlockTime implements BlockTimeStrategy. Timestamp timeouts are checked against the header
+// time of the receiving chain's latest block, as MsgTimeout is, rather than the local clock.
+func (nrs *NaiveStrategy) SetBlockTime(chainID string, t time.Time) {
+	nrs.pendingMu.Lock()
+	defer nrs.pendingMu.Unlock()
+
+	if nrs.blockTimes == nil {
+		nrs.blockTimes = make(map[string]time.Time)
+	}
+	if t.After(nrs.blockTimes[chainID]) {
+		nrs.blockTimes[chainID] = t
+	}
+}
+
+// blockTime returns the header time of the latest block of the chain, zero if none was seen
+func (nrs *NaiveStrategy) blockTime(chainID string) time.Time {
+	nrs.pendingMu.Lock()
+	defer nrs.pendingMu.Unlock()
+	return nrs.blockTimes[chainID]
+}
+
+// untrackPending stops tracking the given packets sent from chain c
+func (nrs *NaiveStrategy) untrackPending(c *Chain, rlyPackets []relayPacket) {
+	nrs.pendingMu.Lock()
+	defer nrs.pendingMu.Unlock()
+
+	for _, rp := range rlyPackets {
+		if _, ok := rp.(*relayMsgRecvPacket); ok {
+			delete(nrs.pending[pendingKey(c)], rp.Seq())
+		}
+	}
+}
+
+// untrackSequences stops tracking the packets sent from chain c with the given sequences, once
+// they were received, acknowledged or timed out by any relay path
+func (nrs *NaiveStrategy) untrackSequences(c *Chain, seqs []uint64) {
+	nrs.pendingMu.Lock()
+	defer nrs.pendingMu.Unlock()
+
+	pending := nrs.pending[pendingKey(c)]
+	for _, seq := range seqs {
+		delete(pending, seq)
+	}
+}
+
+// takeExpired stops tracking and returns the sequences of the packets sent from src that
+// have timed out on dst at the given height and block time
+func (nrs *NaiveStrategy) takeExpired(src, dst *Chain, dsth int64, dstTime time.Time) []uint64 {
+	nrs.pendingMu.Lock()
+	defer nrs.pendingMu.Unlock()
+
+	var (
+		seqs     []uint64
+		pending  = nrs.pending[pendingKey(src)]
+		revision = dst.GetSelfVersion()
+	)
+	for seq, pp := range pending {
+		if pp.expired(revision, dsth, dstTime) {
+			seqs = append(seqs, seq)
+			delete(pending, seq)
+		}
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs
+}
+
+// relayExpiredPackets sends timeouts back to dst for the tracked packets sent from dst that have
+// expired on src, so their funds are refunded without waiting for the next sweep
+func (nrs *NaiveStrategy) relayExpiredPackets(ctx context.Context, src, dst *Chain, srch int64) {
+	seqs := nrs.takeExpired(dst, src, srch, nrs.blockTime(src.ChainID))
+	if len(seqs) == 0 {
+		return
+	}
+
+	// skip any packets that were received before they expired
+	unreceived, err := src.QueryUnreceivedPackets(uint64(srch), seqs)
+	if err != nil {
+		src.Error(fmt.Errorf("failed to query expired packets%v from %s: %w", seqs, dst.ChainID, err))
+		return
+	}
+	if len(unreceived) == 0 {
+		return
+	}
+
+	dst.Log(fmt.Sprintf("- [%s] packets%v sent to %s expired, relaying timeouts",
+		dst.ChainID, unreceived, src.ChainID))
+
+	// RelayPackets sends a MsgTimeout for each packet that has expired on src
+	if err = nrs.RelayPackets(ctx, dst, src, &RelaySequences{Src: unreceived, Dst: []uint64{}}); err != nil {
+		if nrs.FailedPackets == nil {
+			dst.Error(fmt.Errorf("failed to relay timeouts for packets%v: %w", unreceived, err))
+			return
+		}
+
+		dst.Error(fmt.Errorf("failed to relay timeouts for packets%v, queued for retry: %w", unreceived, err))
+		if err = nrs.FailedPackets.Failed(FailedPacketKindPacket, dst, src, unreceived, err); err != nil {
+			dst.Error(err)
+		}
+	}
+}
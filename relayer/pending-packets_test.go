@@ -0,0 +1,105 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingPacketExpired(t *testing.T) {
+	var (
+		blockTime = time.Unix(1600000000, 0)
+		stamp     = uint64(blockTime.UnixNano())
+	)
+
+	tests := []struct {
+		name      string
+		packet    pendingPacket
+		revision  uint64
+		height    int64
+		blockTime time.Time
+		expired   bool
+	}{
+		{"no timeout", pendingPacket{}, 1, 100, blockTime, false},
+		{"before timeout height", pendingPacket{timeout: clienttypes.NewHeight(1, 100)}, 1, 99, blockTime, false},
+		{"at timeout height", pendingPacket{timeout: clienttypes.NewHeight(1, 100)}, 1, 100, blockTime, true},
+		{"after timeout height", pendingPacket{timeout: clienttypes.NewHeight(1, 100)}, 1, 101, blockTime, true},
+		{"later revision", pendingPacket{timeout: clienttypes.NewHeight(1, 100)}, 2, 1, blockTime, true},
+		{"earlier revision", pendingPacket{timeout: clienttypes.NewHeight(2, 100)}, 1, 200, blockTime, false},
+		{"unknown height", pendingPacket{timeout: clienttypes.NewHeight(1, 100)}, 1, 0, blockTime, false},
+		{"before timeout timestamp", pendingPacket{timeoutStamp: stamp + 1}, 1, 100, blockTime, false},
+		{"at timeout timestamp", pendingPacket{timeoutStamp: stamp}, 1, 100, blockTime, true},
+		{"after timeout timestamp", pendingPacket{timeoutStamp: stamp - 1}, 1, 100, blockTime, true},
+		{"unknown block time", pendingPacket{timeoutStamp: 1}, 1, 100, time.Time{}, false},
+		{"timestamp expired first", pendingPacket{timeout: clienttypes.NewHeight(1, 100), timeoutStamp: stamp},
+			1, 50, blockTime, true},
+		{"height expired first", pendingPacket{timeout: clienttypes.NewHeight(1, 100), timeoutStamp: stamp + 1},
+			1, 100, blockTime, true},
+		{"neither expired", pendingPacket{timeout: clienttypes.NewHeight(1, 100), timeoutStamp: stamp + 1},
+			1, 50, blockTime, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expired, tc.packet.expired(tc.revision, tc.height, tc.blockTime))
+		})
+	}
+}
+
+func TestNaiveStrategySetBlockTime(t *testing.T) {
+	var (
+		nrs = &NaiveStrategy{}
+		t0  = time.Unix(1600000000, 0)
+	)
+
+	require.True(t, nrs.blockTime("ibc-0").IsZero())
+
+	nrs.SetBlockTime("ibc-0", t0)
+	require.Equal(t, t0, nrs.blockTime("ibc-0"))
+
+	// block events may arrive out of order, the latest time is kept
+	nrs.SetBlockTime("ibc-0", t0.Add(-time.Second))
+	require.Equal(t, t0, nrs.blockTime("ibc-0"))
+
+	nrs.SetBlockTime("ibc-0", t0.Add(time.Second))
+	require.Equal(t, t0.Add(time.Second), nrs.blockTime("ibc-0"))
+	require.True(t, nrs.blockTime("ibc-1").IsZero())
+}
+
+func TestNaiveStrategyUntrackSequences(t *testing.T) {
+	var (
+		src     = &Chain{ChainID: "ibc-0", PathEnd: &PathEnd{PortID: "transfer", ChannelID: "channel-0"}}
+		other   = &Chain{ChainID: "ibc-0", PathEnd: &PathEnd{PortID: "transfer", ChannelID: "channel-1"}}
+		dst     = &Chain{ChainID: "ibc-1", PathEnd: &PathEnd{PortID: "transfer", ChannelID: "channel-0"}}
+		timeout = clienttypes.NewHeight(1, 10)
+	)
+
+	tests := []struct {
+		name     string
+		untrack  []uint64
+		expected []uint64
+	}{
+		{"none", nil, []uint64{1, 2, 3, 4}},
+		{"received by another path", []uint64{2, 4}, []uint64{1, 3}},
+		{"all", []uint64{1, 2, 3, 4}, nil},
+		{"not tracked", []uint64{5}, []uint64{1, 2, 3, 4}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nrs := &NaiveStrategy{}
+			var rlyPackets []relayPacket
+			for seq := uint64(1); seq <= 4; seq++ {
+				rlyPackets = append(rlyPackets, &relayMsgRecvPacket{seq: seq, timeout: timeout})
+			}
+			nrs.trackPending(src, rlyPackets)
+			nrs.trackPending(other, rlyPackets)
+
+			nrs.untrackSequences(src, tc.untrack)
+
+			require.Equal(t, tc.expected, nrs.takeExpired(src, dst, 10, time.Time{}))
+			// packets sent over another channel are still tracked
+			require.Equal(t, []uint64{1, 2, 3, 4}, nrs.takeExpired(other, dst, 10, time.Time{}))
+		})
+	}
+}
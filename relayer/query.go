@@ -26,6 +26,7 @@ import (
 	chanutils "github.com/cosmos/ibc-go/v2/modules/core/04-channel/client/utils"
 	chantypes "github.com/cosmos/ibc-go/v2/modules/core/04-channel/types"
 	committypes "github.com/cosmos/ibc-go/v2/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v2/modules/core/24-host"
 	ibcexported "github.com/cosmos/ibc-go/v2/modules/core/exported"
 	tmclient "github.com/cosmos/ibc-go/v2/modules/light-clients/07-tendermint/types"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -50,12 +51,12 @@ func (c *Chain) QueryBalance(keyName string) (sdk.Coins, error) {
 	if keyName == "" {
 		addr = c.MustGetAddress()
 	} else {
-		info, err := c.Keybase.Key(keyName)
+		keyAddr, err := c.KeyAddress(keyName)
 		if err != nil {
 			return nil, err
 		}
 		done := c.UseSDKContext()
-		addr = info.GetAddress().String()
+		addr = keyAddr.String()
 		done()
 	}
 	return c.QueryBalanceWithAddress(addr)
@@ -77,7 +78,7 @@ func (c *Chain) QueryBalanceWithAddress(address string) (sdk.Coins, error) {
 		CountTotal: true,
 	})
 
-	queryClient := bankTypes.NewQueryClient(c.CLIContext(0))
+	queryClient := bankTypes.NewQueryClient(c.QueryConn(0))
 
 	res, err := queryClient.AllBalances(context.Background(), params)
 	if err != nil {
@@ -100,16 +101,34 @@ func (c *Chain) QueryConsensusState(height int64) (*tmclient.ConsensusState, int
 // QueryClientConsensusState retrevies the latest consensus state for a client in state at a given height
 func (c *Chain) QueryClientConsensusState(
 	height int64, dstClientConsHeight ibcexported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
-	return clientutils.QueryConsensusStateABCI(
+	res, err := clientutils.QueryConsensusStateABCI(
 		c.CLIContext(height),
 		c.PathEnd.ClientID,
 		dstClientConsHeight,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	key := host.FullConsensusStateKey(c.PathEnd.ClientID, dstClientConsHeight)
+	if err = c.verifyIBCProofOf(key, res.ConsensusState, res.Proof, res.ProofHeight); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 // QueryClientStateResponse retrevies the latest consensus state for a client in state at a given height
 func (c *Chain) QueryClientStateResponse(height int64) (*clienttypes.QueryClientStateResponse, error) {
-	return clientutils.QueryClientStateABCI(c.CLIContext(height), c.PathEnd.ClientID)
+	res, err := clientutils.QueryClientStateABCI(c.CLIContext(height), c.PathEnd.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := host.FullClientStateKey(c.PathEnd.ClientID)
+	if err = c.verifyIBCProofOf(key, res.ClientState, res.Proof, res.ProofHeight); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 // QueryClientState retrevies the latest consensus state for a client in state at a given height
@@ -158,7 +177,7 @@ func CastClientStateToTMType(cs *codectypes.Any) (*tmclient.ClientState, error)
 
 // QueryClients queries all the clients!
 func (c *Chain) QueryClients(pagereq *querytypes.PageRequest) (*clienttypes.QueryClientStatesResponse, error) {
-	qc := clienttypes.NewQueryClient(c.CLIContext(0))
+	qc := clienttypes.NewQueryClient(c.QueryConn(0))
 	return qc.ClientStates(context.Background(), &clienttypes.QueryClientStatesRequest{
 		Pagination: pagereq,
 	})
@@ -180,7 +199,7 @@ func DefaultPageRequest() *querytypes.PageRequest {
 // QueryConnections gets any connections on a chain
 func (c *Chain) QueryConnections(
 	pagereq *querytypes.PageRequest) (conns *conntypes.QueryConnectionsResponse, err error) {
-	qc := conntypes.NewQueryClient(c.CLIContext(0))
+	qc := conntypes.NewQueryClient(c.QueryConn(0))
 	res, err := qc.Connections(context.Background(), &conntypes.QueryConnectionsRequest{
 		Pagination: pagereq,
 	})
@@ -201,6 +220,11 @@ func (c *Chain) QueryConnection(height int64) (*conntypes.QueryConnectionRespons
 	} else if err != nil {
 		return nil, err
 	}
+
+	key := host.ConnectionKey(c.PathEnd.ConnectionID)
+	if err = c.verifyIBCProofOf(key, res.Connection, res.Proof, res.ProofHeight); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
@@ -245,7 +269,7 @@ func QueryConnectionPair(
 func (c *Chain) QueryConnectionChannels(
 	connectionID string,
 	pagereq *querytypes.PageRequest) (*chantypes.QueryConnectionChannelsResponse, error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(0))
+	qc := chantypes.NewQueryClient(c.QueryConn(0))
 	return qc.ConnectionChannels(context.Background(), &chantypes.QueryConnectionChannelsRequest{
 		Connection: connectionID,
 		Pagination: pagereq,
@@ -260,6 +284,11 @@ func (c *Chain) QueryChannel(height int64) (chanRes *chantypes.QueryChannelRespo
 	} else if err != nil {
 		return nil, err
 	}
+
+	key := host.ChannelKey(c.PathEnd.PortID, c.PathEnd.ChannelID)
+	if err = c.verifyIBCProofOf(key, res.Channel, res.Proof, res.ProofHeight); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
@@ -295,7 +324,7 @@ func QueryChannelPair(src, dst *Chain, srcH, dstH int64) (srcChan, dstChan *chan
 
 // QueryChannels returns all the channels that are registered on a chain
 func (c *Chain) QueryChannels(pagereq *querytypes.PageRequest) (*chantypes.QueryChannelsResponse, error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(0))
+	qc := chantypes.NewQueryClient(c.QueryConn(0))
 	res, err := qc.Channels(context.Background(), &chantypes.QueryChannelsRequest{
 		Pagination: pagereq,
 	})
@@ -304,7 +333,7 @@ func (c *Chain) QueryChannels(pagereq *querytypes.PageRequest) (*chantypes.Query
 
 // QueryChannelClient returns the client state of the client supporting a given channel
 func (c *Chain) QueryChannelClient(height int64) (*chantypes.QueryChannelClientStateResponse, error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(height))
+	qc := chantypes.NewQueryClient(c.QueryConn(height))
 	return qc.ChannelClientState(context.Background(), &chantypes.QueryChannelClientStateRequest{
 		PortId:    c.PathEnd.PortID,
 		ChannelId: c.PathEnd.ChannelID,
@@ -317,7 +346,7 @@ func (c *Chain) QueryChannelClient(height int64) (*chantypes.QueryChannelClientS
 
 // QueryDenomTrace takes a denom from IBC and queries the information about it
 func (c *Chain) QueryDenomTrace(denom string) (*transfertypes.QueryDenomTraceResponse, error) {
-	return transfertypes.NewQueryClient(c.CLIContext(0)).DenomTrace(context.Background(),
+	return transfertypes.NewQueryClient(c.QueryConn(0)).DenomTrace(context.Background(),
 		&transfertypes.QueryDenomTraceRequest{
 			Hash: denom,
 		})
@@ -326,7 +355,7 @@ func (c *Chain) QueryDenomTrace(denom string) (*transfertypes.QueryDenomTraceRes
 // QueryDenomTraces returns all the denom traces from a given chain
 func (c *Chain) QueryDenomTraces(pagereq *querytypes.PageRequest,
 	height int64) (*transfertypes.QueryDenomTracesResponse, error) {
-	return transfertypes.NewQueryClient(c.CLIContext(height)).DenomTraces(context.Background(),
+	return transfertypes.NewQueryClient(c.QueryConn(height)).DenomTraces(context.Background(),
 		&transfertypes.QueryDenomTracesRequest{
 			Pagination: pagereq,
 		})
@@ -339,7 +368,7 @@ func (c *Chain) QueryDenomTraces(pagereq *querytypes.PageRequest,
 // QueryHistoricalInfo returns historical header data
 func (c *Chain) QueryHistoricalInfo(height clienttypes.Height) (*stakingtypes.QueryHistoricalInfoResponse, error) {
 	//TODO: use epoch number in query once SDK gets updated
-	qc := stakingtypes.NewQueryClient(c.CLIContext(0))
+	qc := stakingtypes.NewQueryClient(c.QueryConn(0))
 	return qc.HistoricalInfo(context.Background(), &stakingtypes.QueryHistoricalInfoRequest{
 		Height: int64(height.GetRevisionHeight()),
 	})
@@ -347,6 +376,10 @@ func (c *Chain) QueryHistoricalInfo(height clienttypes.Height) (*stakingtypes.Qu
 
 // QueryValsetAtHeight returns the validator set at a given height
 func (c *Chain) QueryValsetAtHeight(height clienttypes.Height) (*tmproto.ValidatorSet, error) {
+	if vs, ok := c.cachedValset(int64(height.RevisionHeight)); ok {
+		return vs, nil
+	}
+
 	res, err := c.QueryHistoricalInfo(height)
 	if err != nil {
 		return nil, fmt.Errorf("chain(%s): %s", c.ChainID, err)
@@ -364,7 +397,12 @@ func (c *Chain) QueryValsetAtHeight(height clienttypes.Height) (*tmproto.Validat
 	}
 	tmValSet.GetProposer()
 
-	return tmValSet.ToProto()
+	vs, err := tmValSet.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	c.cacheValset(int64(height.RevisionHeight), vs)
+	return vs, nil
 }
 
 func (c *Chain) toTmValidators(vals stakingtypes.Validators) ([]*tmtypes.Validator, error) {
@@ -396,7 +434,7 @@ func (c *Chain) toTmValidator(val stakingtypes.Validator) (*tmtypes.Validator, e
 func (c *Chain) QueryUnbondingPeriod() (time.Duration, error) {
 	req := stakingtypes.QueryParamsRequest{}
 
-	queryClient := stakingtypes.NewQueryClient(c.CLIContext(0))
+	queryClient := stakingtypes.NewQueryClient(c.QueryConn(0))
 
 	res, err := queryClient.Params(context.Background(), &req)
 	if err != nil {
@@ -410,7 +448,7 @@ func (c *Chain) QueryUnbondingPeriod() (time.Duration, error) {
 func (c *Chain) QueryUpgradedClient(height int64) (*codectypes.Any, []byte, clienttypes.Height, error) {
 	req := clienttypes.QueryUpgradedClientStateRequest{}
 
-	queryClient := clienttypes.NewQueryClient(c.CLIContext(0))
+	queryClient := clienttypes.NewQueryClient(c.QueryConn(0))
 
 	res, err := queryClient.UpgradedClientState(context.Background(), &req)
 	if err != nil {
@@ -435,7 +473,7 @@ func (c *Chain) QueryUpgradedClient(height int64) (*codectypes.Any, []byte, clie
 func (c *Chain) QueryUpgradedConsState(height int64) (*codectypes.Any, []byte, clienttypes.Height, error) {
 	req := clienttypes.QueryUpgradedConsensusStateRequest{}
 
-	queryClient := clienttypes.NewQueryClient(c.CLIContext(height))
+	queryClient := clienttypes.NewQueryClient(c.QueryConn(height))
 
 	res, err := queryClient.UpgradedConsensusState(context.Background(), &req)
 	if err != nil {
@@ -512,31 +550,73 @@ func (c *Chain) WaitForNBlocks(n int64) error {
 
 // QueryNextSeqRecv returns the next seqRecv for a configured channel
 func (c *Chain) QueryNextSeqRecv(height int64) (recvRes *chantypes.QueryNextSequenceReceiveResponse, err error) {
-	return chanutils.QueryNextSequenceReceive(c.CLIContext(height),
+	recvRes, err = chanutils.QueryNextSequenceReceive(c.CLIContext(height),
 		c.PathEnd.PortID, c.PathEnd.ChannelID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	key := host.NextSequenceRecvKey(c.PathEnd.PortID, c.PathEnd.ChannelID)
+	value := sdk.Uint64ToBigEndian(recvRes.NextSequenceReceive)
+	if err = c.verifyIBCProof(key, value, recvRes.Proof, recvRes.ProofHeight); err != nil {
+		return nil, err
+	}
+	return recvRes, nil
 }
 
 // QueryPacketCommitment returns the packet commitment proof at a given height
 func (c *Chain) QueryPacketCommitment(
 	height int64, seq uint64) (comRes *chantypes.QueryPacketCommitmentResponse, err error) {
-	return chanutils.QueryPacketCommitment(c.CLIContext(height), c.PathEnd.PortID, c.PathEnd.ChannelID, seq, true)
+	comRes, err = chanutils.QueryPacketCommitment(c.CLIContext(height), c.PathEnd.PortID, c.PathEnd.ChannelID, seq, true)
+	if err != nil {
+		return nil, err
+	}
+
+	key := host.PacketCommitmentKey(c.PathEnd.PortID, c.PathEnd.ChannelID, seq)
+	if err = c.verifyIBCProof(key, comRes.Commitment, comRes.Proof, comRes.ProofHeight); err != nil {
+		return nil, err
+	}
+	return comRes, nil
 }
 
 // QueryPacketAcknowledgement returns the packet ack proof at a given height
 func (c *Chain) QueryPacketAcknowledgement(height int64,
 	seq uint64) (ackRes *chantypes.QueryPacketAcknowledgementResponse, err error) {
-	return chanutils.QueryPacketAcknowledgement(c.CLIContext(height), c.PathEnd.PortID, c.PathEnd.ChannelID, seq, true)
+	ackRes, err = chanutils.QueryPacketAcknowledgement(c.CLIContext(height), c.PathEnd.PortID, c.PathEnd.ChannelID, seq, true)
+	if err != nil {
+		return nil, err
+	}
+
+	key := host.PacketAcknowledgementKey(c.PathEnd.PortID, c.PathEnd.ChannelID, seq)
+	if err = c.verifyIBCProof(key, ackRes.Acknowledgement, ackRes.Proof, ackRes.ProofHeight); err != nil {
+		return nil, err
+	}
+	return ackRes, nil
 }
 
 // QueryPacketReceipt returns the packet receipt proof at a given height
 func (c *Chain) QueryPacketReceipt(height int64, seq uint64) (recRes *chantypes.QueryPacketReceiptResponse, err error) {
-	return chanutils.QueryPacketReceipt(c.CLIContext(height), c.PathEnd.PortID, c.PathEnd.ChannelID, seq, true)
+	recRes, err = chanutils.QueryPacketReceipt(c.CLIContext(height), c.PathEnd.PortID, c.PathEnd.ChannelID, seq, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// a received packet's receipt is a single byte, an unreceived one must be proven absent
+	var value []byte
+	if recRes.Received {
+		value = []byte{byte(1)}
+	}
+	key := host.PacketReceiptKey(c.PathEnd.PortID, c.PathEnd.ChannelID, seq)
+	if err = c.verifyIBCProof(key, value, recRes.Proof, recRes.ProofHeight); err != nil {
+		return nil, err
+	}
+	return recRes, nil
 }
 
 // QueryPacketCommitments returns an array of packet commitments
 func (c *Chain) QueryPacketCommitments(pagereq *querytypes.PageRequest,
 	height uint64) (comRes *chantypes.QueryPacketCommitmentsResponse, err error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(int64(height)))
+	qc := chantypes.NewQueryClient(c.QueryConn(int64(height)))
 	return qc.PacketCommitments(context.Background(), &chantypes.QueryPacketCommitmentsRequest{
 		PortId:     c.PathEnd.PortID,
 		ChannelId:  c.PathEnd.ChannelID,
@@ -547,7 +627,7 @@ func (c *Chain) QueryPacketCommitments(pagereq *querytypes.PageRequest,
 // QueryPacketAcknowledgements returns an array of packet acks
 func (c *Chain) QueryPacketAcknowledgements(pagereq *querytypes.PageRequest,
 	height uint64) (comRes *chantypes.QueryPacketAcknowledgementsResponse, err error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(int64(height)))
+	qc := chantypes.NewQueryClient(c.QueryConn(int64(height)))
 	return qc.PacketAcknowledgements(context.Background(), &chantypes.QueryPacketAcknowledgementsRequest{
 		PortId:     c.PathEnd.PortID,
 		ChannelId:  c.PathEnd.ChannelID,
@@ -557,7 +637,7 @@ func (c *Chain) QueryPacketAcknowledgements(pagereq *querytypes.PageRequest,
 
 // QueryUnreceivedPackets returns a list of unrelayed packet commitments
 func (c *Chain) QueryUnreceivedPackets(height uint64, seqs []uint64) ([]uint64, error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(int64(height)))
+	qc := chantypes.NewQueryClient(c.QueryConn(int64(height)))
 	res, err := qc.UnreceivedPackets(context.Background(), &chantypes.QueryUnreceivedPacketsRequest{
 		PortId:                    c.PathEnd.PortID,
 		ChannelId:                 c.PathEnd.ChannelID,
@@ -571,7 +651,7 @@ func (c *Chain) QueryUnreceivedPackets(height uint64, seqs []uint64) ([]uint64,
 
 // QueryUnreceivedAcknowledgements returns a list of unrelayed packet acks
 func (c *Chain) QueryUnreceivedAcknowledgements(height uint64, seqs []uint64) ([]uint64, error) {
-	qc := chantypes.NewQueryClient(c.CLIContext(int64(height)))
+	qc := chantypes.NewQueryClient(c.QueryConn(int64(height)))
 	res, err := qc.UnreceivedAcks(context.Background(), &chantypes.QueryUnreceivedAcksRequest{
 		PortId:             c.PathEnd.PortID,
 		ChannelId:          c.PathEnd.ChannelID,
@@ -639,11 +719,13 @@ func (c *Chain) QueryABCI(req abci.RequestQuery) (res abci.ResponseQuery, err er
 	}
 
 	// data from trusted node or subspace query doesn't need verification
-	if !isQueryStoreWithProof(req.Path) {
+	if !req.Prove || !isQueryStoreWithProof("/"+strings.TrimPrefix(req.Path, "/")) {
 		return result.Response, nil
 	}
 
-	// TODO: figure out how to verify queries?
+	if err = c.verifyABCIResponse(req.Path, result.Response); err != nil {
+		return res, err
+	}
 
 	return result.Response, nil
 }
@@ -695,6 +777,10 @@ func (c *Chain) QueryHeaderAtHeight(height int64) (*tmclient.Header, error) {
 		return nil, fmt.Errorf("must pass in valid height, %d not valid", height)
 	}
 
+	if header, ok := c.cachedHeader(height); ok {
+		return header, nil
+	}
+
 	res, err := c.Client.Commit(context.Background(), &height)
 	if err != nil {
 		return nil, err
@@ -710,12 +796,14 @@ func (c *Chain) QueryHeaderAtHeight(height int64) (*tmclient.Header, error) {
 		return nil, err
 	}
 
-	return &tmclient.Header{
+	header := &tmclient.Header{
 		// NOTE: This is not a SignedHeader
 		// We are missing a light.Commit type here
 		SignedHeader: res.SignedHeader.ToProto(),
 		ValidatorSet: protoVal,
-	}, nil
+	}
+	c.cacheHeader(height, header)
+	return header, nil
 }
 
 // isQueryStoreWithProof expects a format like /<queryType>/<storeName>/<subpath>
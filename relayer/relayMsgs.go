@@ -1,8 +1,11 @@
 package relayer
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/gogo/protobuf/proto"
 
@@ -22,15 +25,20 @@ type DeliverMsgsAction struct {
 
 // RelayMsgs contains the msgs that need to be sent to both a src and dst chain
 // after a given relay round. MaxTxSize and MaxMsgLength are ignored if they are
-// set to zero.
+// set to zero. If Ordered is set no batch is sent to a chain after a batch to that
+// chain failed, so later msgs are never delivered ahead of earlier ones.
 type RelayMsgs struct {
 	Src          []sdk.Msg `json:"src"`
 	Dst          []sdk.Msg `json:"dst"`
 	MaxTxSize    uint64    `json:"max_tx_size"`    // maximum permitted size of the msgs in a bundled relay transaction
 	MaxMsgLength uint64    `json:"max_msg_length"` // maximum amount of messages in a bundled relay transaction
+	Ordered      bool      `json:"ordered"`        // stop sending to a chain after a failed batch
 
-	Last      bool `json:"last"`
-	Succeeded bool `json:"success"`
+	Last         bool `json:"last"`
+	Succeeded    bool `json:"success"`
+	SrcSucceeded bool `json:"src_success"`   // every batch sent to the src chain succeeded
+	DstSucceeded bool `json:"dst_success"`   // every batch sent to the dst chain succeeded
+	NotCommitted bool `json:"not_committed"` // a batch was broadcast but not committed before its confirm timeout
 }
 
 // NewRelayMsgs returns an initialized version of relay messages
@@ -60,10 +68,22 @@ func (r *RelayMsgs) IsMaxTx(msgLen, txSize uint64) bool {
 		(r.MaxTxSize != 0 && txSize > r.MaxTxSize)
 }
 
-// Send sends the messages with appropriate output
-// TODO: Parallelize? Maybe?
-func (r *RelayMsgs) Send(src, dst *Chain) {
-	r.SendWithController(src, dst, true)
+// Err returns nil if the messages were sent successfully. If a batch was broadcast but never
+// committed the error wraps ErrTxNotCommitted.
+func (r *RelayMsgs) Err() error {
+	switch {
+	case r.Succeeded:
+		return nil
+	case r.NotCommitted:
+		return fmt.Errorf("failed to send msgs, a batch wasn't committed: %w", ErrTxNotCommitted)
+	default:
+		return fmt.Errorf("failed to send msgs, see above logs for details")
+	}
+}
+
+// Send sends the messages with appropriate output, submitting to src and dst concurrently
+func (r *RelayMsgs) Send(ctx context.Context, src, dst *Chain) {
+	r.SendWithController(ctx, src, dst, true)
 }
 
 func EncodeMsgs(c *Chain, msgs []sdk.Msg) []string {
@@ -93,7 +113,7 @@ func DecodeMsgs(c *Chain, msgs []string) []sdk.Msg {
 	return outMsgs
 }
 
-func (r *RelayMsgs) SendWithController(src, dst *Chain, useController bool) {
+func (r *RelayMsgs) SendWithController(ctx context.Context, src, dst *Chain, useController bool) {
 	if useController && SendToController != nil {
 		action := &DeliverMsgsAction{
 			Src:       MarshalChain(src),
@@ -115,58 +135,57 @@ func (r *RelayMsgs) SendWithController(src, dst *Chain, useController bool) {
 			} else {
 				r.Succeeded = true
 			}
+			r.SrcSucceeded, r.DstSucceeded = r.Succeeded, r.Succeeded
 			return
 		}
 	}
 
+	// submit to both chains concurrently so a slow chain doesn't hold up the other
+	var (
+		wg                               sync.WaitGroup
+		srcNotCommitted, dstNotCommitted bool
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		r.SrcSucceeded, srcNotCommitted = r.sendBatches(ctx, src, r.Src)
+	}()
+	go func() {
+		defer wg.Done()
+		r.DstSucceeded, dstNotCommitted = r.sendBatches(ctx, dst, r.Dst)
+	}()
+	wg.Wait()
+
+	r.Succeeded = r.SrcSucceeded && r.DstSucceeded
+	r.NotCommitted = srcNotCommitted || dstNotCommitted
+}
+
+// sendBatches submits msgs to chain c in batches constrained by MaxTxSize and MaxMsgLength. It
+// returns true if every batch succeeded and whether a batch was broadcast but never committed.
+func (r *RelayMsgs) sendBatches(ctx context.Context, c *Chain, msgs []sdk.Msg) (bool, bool) {
 	//nolint:prealloc // can not be pre allocated
 	var (
 		msgLen, txSize uint64
-		msgs           []sdk.Msg
+		batch          []sdk.Msg
+		succeeded      = true
+		notCommitted   bool
 	)
 
-	r.Succeeded = true
-
-	// submit batches of relay transactions
-	for _, msg := range r.Src {
-		bz, err := proto.Marshal(msg)
-		if err != nil {
-			panic(err)
-		}
-
-		msgLen++
-		txSize += uint64(len(bz))
-
-		if r.IsMaxTx(msgLen, txSize) {
-			// Submit the transactions to src chain and update its status
-			res, success, err := src.SendMsgs(msgs)
-			if err != nil {
-				src.LogFailedTx(res, err, msgs)
-			}
-			r.Succeeded = r.Succeeded && success
-
-			// clear the current batch and reset variables
-			msgLen, txSize = 1, uint64(len(bz))
-			msgs = []sdk.Msg{}
+	send := func() {
+		// an ordered relay must not deliver later msgs after an earlier batch failed
+		if r.Ordered && !succeeded {
+			return
 		}
-		msgs = append(msgs, msg)
-	}
 
-	// submit leftover msgs
-	if len(msgs) > 0 {
-		res, success, err := src.SendMsgs(msgs)
+		res, success, err := c.SendMsgs(ctx, batch)
 		if err != nil {
-			src.LogFailedTx(res, err, msgs)
+			c.LogFailedTx(res, err, batch)
 		}
-
-		r.Succeeded = success
+		succeeded = succeeded && success
+		notCommitted = notCommitted || errors.Is(err, ErrTxNotCommitted)
 	}
 
-	// reset variables
-	msgLen, txSize = 0, 0
-	msgs = []sdk.Msg{}
-
-	for _, msg := range r.Dst {
+	for _, msg := range msgs {
 		bz, err := proto.Marshal(msg)
 		if err != nil {
 			panic(err)
@@ -176,30 +195,22 @@ func (r *RelayMsgs) SendWithController(src, dst *Chain, useController bool) {
 		txSize += uint64(len(bz))
 
 		if r.IsMaxTx(msgLen, txSize) {
-			// Submit the transaction to dst chain and update its status
-			res, success, err := dst.SendMsgs(msgs)
-			if err != nil {
-				dst.LogFailedTx(res, err, msgs)
-			}
-
-			r.Succeeded = r.Succeeded && success
+			// Submit the transactions to the chain and update its status
+			send()
 
 			// clear the current batch and reset variables
 			msgLen, txSize = 1, uint64(len(bz))
-			msgs = []sdk.Msg{}
+			batch = []sdk.Msg{}
 		}
-		msgs = append(msgs, msg)
+		batch = append(batch, msg)
 	}
 
 	// submit leftover msgs
-	if len(msgs) > 0 {
-		res, success, err := dst.SendMsgs(msgs)
-		if err != nil {
-			dst.LogFailedTx(res, err, msgs)
-		}
-
-		r.Succeeded = success
+	if len(batch) > 0 {
+		send()
 	}
+
+	return succeeded, notCommitted
 }
 
 func getMsgAction(msgs []sdk.Msg) string {
@@ -0,0 +1,284 @@
+package relayer
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/gorilla/mux"
+)
+
+// RemoteSignerTimeout is how long a request to a remote signer may take
+var RemoteSignerTimeout = 10 * time.Second
+
+// SignerPubKeyResponse is the response of a signer daemon to a public key request
+type SignerPubKeyResponse struct {
+	PubKey json.RawMessage `json:"pub_key"`
+}
+
+// SignerSignRequest is a request to a signer daemon to sign bytes
+type SignerSignRequest struct {
+	SignBytes []byte `json:"sign_bytes"`
+}
+
+// SignerSignResponse is the response of a signer daemon to a sign request
+type SignerSignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// remoteSigner signs with the keys held by a signer daemon, see SignerDaemon. The keys of a
+// chain are served under <addr>/chains/<chain-id>/keys/<name>.
+type remoteSigner struct {
+	base   *url.URL
+	token  string
+	cdc    codec.Codec
+	client *http.Client
+
+	// public keys don't change, so they're only requested once
+	mu      sync.Mutex
+	pubKeys map[string]cryptotypes.PubKey
+}
+
+// NewRemoteSigner returns a Signer that signs with the keys of chainID held by the signer
+// daemon at addr, an http or https URL, authenticating with the daemon's token
+func NewRemoteSigner(addr, chainID, token string, cdc codec.Codec) (Signer, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signer (%s) for chain %s: %w", addr, chainID, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("signer (%s) for chain %s must be an http or https URL", addr, chainID)
+	}
+	u.Path = path.Join(u.Path, "chains", chainID, "keys")
+
+	return &remoteSigner{
+		base:    u,
+		token:   token,
+		cdc:     cdc,
+		client:  &http.Client{Timeout: RemoteSignerTimeout},
+		pubKeys: make(map[string]cryptotypes.PubKey),
+	}, nil
+}
+
+// PubKey implements Signer
+func (rs *remoteSigner) PubKey(name string) (cryptotypes.PubKey, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if pk, ok := rs.pubKeys[name]; ok {
+		return pk, nil
+	}
+
+	var res SignerPubKeyResponse
+	if err := rs.do(http.MethodGet, rs.keyURL(name), nil, &res); err != nil {
+		return nil, err
+	}
+
+	var pk cryptotypes.PubKey
+	if err := rs.cdc.UnmarshalInterfaceJSON(res.PubKey, &pk); err != nil {
+		return nil, fmt.Errorf("failed to decode public key of key %s from signer: %w", name, err)
+	}
+	rs.pubKeys[name] = pk
+	return pk, nil
+}
+
+// Sign implements Signer
+func (rs *remoteSigner) Sign(name string, msg []byte) ([]byte, error) {
+	var res SignerSignResponse
+	if err := rs.do(http.MethodPost, rs.keyURL(name)+"/sign", SignerSignRequest{SignBytes: msg}, &res); err != nil {
+		return nil, err
+	}
+	return res.Signature, nil
+}
+
+func (rs *remoteSigner) keyURL(name string) string {
+	u := *rs.base
+	u.Path = path.Join(u.Path, name)
+	return u.String()
+}
+
+// do sends a request with the JSON of body, if any, and decodes the JSON response into out
+func (rs *remoteSigner) do(method, u string, body, out interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bz
+	}
+
+	req, err := http.NewRequest(method, u, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+rs.token)
+
+	resp, err := rs.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bz, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errRes struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(bz, &errRes) == nil && errRes.Error != "" {
+			return fmt.Errorf("signer: %s", errRes.Error)
+		}
+		return fmt.Errorf("signer: %s", resp.Status)
+	}
+	return json.Unmarshal(bz, out)
+}
+
+// ErrSignDocNotAllowed is returned by a signer daemon for sign bytes that aren't the sign doc of
+// a relay transaction
+var ErrSignDocNotAllowed = errors.New("sign doc not allowed")
+
+// SignerDaemon returns a handler that serves the keys of the local keyrings of the given chains
+// to remote signers. It is a reference implementation; the keys it serves are only as safe as
+// the host it runs on.
+//
+// Requests must carry token as a bearer token. Only SIGN_MODE_DIRECT sign docs for one of the
+// chains whose msgs are all relay msgs, see RelayMsgTypeURLs, are signed, directly or wrapped in
+// authz MsgExecs. A stolen token therefore can't be used to move the funds of a key.
+func SignerDaemon(token string, chains ...*Chain) http.Handler {
+	byID := make(map[string]*Chain, len(chains))
+	for _, c := range chains {
+		byID[c.ChainID] = c
+	}
+
+	chain := func(w http.ResponseWriter, r *http.Request) (*Chain, Signer, string, bool) {
+		vars := mux.Vars(r)
+		c, ok := byID[vars["chain-id"]]
+		if !ok {
+			respondWithError(w, http.StatusNotFound, fmt.Sprintf("chain %s not found", vars["chain-id"]))
+			return nil, nil, "", false
+		}
+		return c, NewKeyringSigner(c.Keybase), vars["name"], true
+	}
+
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+				respondWithError(w, http.StatusUnauthorized, "invalid signer token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	r.HandleFunc("/chains/{chain-id}/keys/{name}", func(w http.ResponseWriter, r *http.Request) {
+		c, s, name, ok := chain(w, r)
+		if !ok {
+			return
+		}
+
+		pk, err := s.PubKey(name)
+		if err != nil {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		bz, err := c.Encoding.Marshaler.MarshalInterfaceJSON(pk)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondWithJSON(w, http.StatusOK, SignerPubKeyResponse{PubKey: bz})
+	}).Methods("GET")
+
+	r.HandleFunc("/chains/{chain-id}/keys/{name}/sign", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		c, s, name, ok := chain(w, r)
+		if !ok {
+			return
+		}
+
+		var req SignerSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Failed to unmarshal request payload: %s", err))
+			return
+		}
+
+		if err := checkSignDoc(c.ChainID, req.SignBytes); err != nil {
+			c.Error(fmt.Errorf("refused to sign with key %s: %w", name, err))
+			respondWithError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		sig, err := s.Sign(name, req.SignBytes)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Log(fmt.Sprintf("- [%s] signed %d bytes with key %s", c.ChainID, len(req.SignBytes), name))
+		respondWithJSON(w, http.StatusOK, SignerSignResponse{Signature: sig})
+	}).Methods("POST")
+
+	return r
+}
+
+// checkSignDoc returns ErrSignDocNotAllowed unless signBytes are the SIGN_MODE_DIRECT sign doc of
+// a transaction on chainID that only has relay msgs, directly or in authz MsgExecs
+func checkSignDoc(chainID string, signBytes []byte) error {
+	var doc txtypes.SignDoc
+	if err := doc.Unmarshal(signBytes); err != nil {
+		return fmt.Errorf("%w: not a sign doc: %v", ErrSignDocNotAllowed, err)
+	}
+	if doc.ChainId != chainID {
+		return fmt.Errorf("%w: sign doc is for chain %s", ErrSignDocNotAllowed, doc.ChainId)
+	}
+
+	var body txtypes.TxBody
+	if err := body.Unmarshal(doc.BodyBytes); err != nil {
+		return fmt.Errorf("%w: invalid tx body: %v", ErrSignDocNotAllowed, err)
+	}
+	if len(body.Messages) == 0 {
+		return fmt.Errorf("%w: tx has no msgs", ErrSignDocNotAllowed)
+	}
+
+	execURL := sdk.MsgTypeURL(&authz.MsgExec{})
+	for _, msg := range body.Messages {
+		if msg.TypeUrl != execURL {
+			if !isRelayMsgTypeURL(msg.TypeUrl) {
+				return fmt.Errorf("%w: %s isn't a relay msg", ErrSignDocNotAllowed, msg.TypeUrl)
+			}
+			continue
+		}
+
+		var exec authz.MsgExec
+		if err := exec.Unmarshal(msg.Value); err != nil {
+			return fmt.Errorf("%w: invalid %s: %v", ErrSignDocNotAllowed, execURL, err)
+		}
+		for _, inner := range exec.Msgs {
+			if !isRelayMsgTypeURL(inner.TypeUrl) {
+				return fmt.Errorf("%w: %s in %s isn't a relay msg", ErrSignDocNotAllowed, inner.TypeUrl, execURL)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package relayer
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v2/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const testSignerToken = "secret"
+
+// newTestSignerChain returns a chain with a key named relayer in an in-memory keyring
+func newTestSignerChain(t *testing.T) *Chain {
+	kr, err := keyring.New("ibc-0", keyring.BackendMemory, "", nil)
+	require.NoError(t, err)
+	_, _, err = kr.NewMnemonic("relayer", keyring.English, sdk.FullFundraiserPath, "", hd.Secp256k1)
+	require.NoError(t, err)
+
+	c := &Chain{ChainID: "ibc-0", AccountPrefix: "cosmos", Keybase: kr, logger: log.NewNopLogger()}
+	c.Encoding = c.MakeEncodingConfig()
+	return c
+}
+
+// testSignDoc returns the sign bytes of a transaction on chainID with msgs
+func testSignDoc(t *testing.T, chainID string, msgs ...sdk.Msg) []byte {
+	body := txtypes.TxBody{}
+	for _, msg := range msgs {
+		any, err := codectypes.NewAnyWithValue(msg)
+		require.NoError(t, err)
+		body.Messages = append(body.Messages, any)
+	}
+	bodyBytes, err := body.Marshal()
+	require.NoError(t, err)
+
+	doc := txtypes.SignDoc{BodyBytes: bodyBytes, ChainId: chainID}
+	bz, err := doc.Marshal()
+	require.NoError(t, err)
+	return bz
+}
+
+func TestSignerDaemon(t *testing.T) {
+	c := newTestSignerChain(t)
+	srv := httptest.NewServer(SignerDaemon(testSignerToken, c))
+	defer srv.Close()
+
+	signer, err := NewRemoteSigner(srv.URL, c.ChainID, testSignerToken, c.Encoding.Marshaler)
+	require.NoError(t, err)
+	pk, err := signer.PubKey("relayer")
+	require.NoError(t, err)
+
+	var (
+		addr      = sdk.AccAddress(pk.Address())
+		recv      = &chantypes.MsgRecvPacket{}
+		update    = &clienttypes.MsgUpdateClient{}
+		send      = banktypes.NewMsgSend(addr, addr, sdk.NewCoins(sdk.NewInt64Coin("uatom", 1)))
+		execRelay = authz.NewMsgExec(addr, []sdk.Msg{update, recv})
+		execSend  = authz.NewMsgExec(addr, []sdk.Msg{update, send})
+	)
+
+	tests := []struct {
+		name      string
+		signBytes []byte
+		allowed   bool
+	}{
+		{"relay msgs", testSignDoc(t, c.ChainID, update, recv), true},
+		{"relay msgs in exec", testSignDoc(t, c.ChainID, &execRelay), true},
+		{"send", testSignDoc(t, c.ChainID, update, send), false},
+		{"send in exec", testSignDoc(t, c.ChainID, &execSend), false},
+		{"no msgs", testSignDoc(t, c.ChainID), false},
+		{"other chain", testSignDoc(t, "ibc-1", update, recv), false},
+		{"not a sign doc", []byte("sign bytes"), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sig, err := signer.Sign("relayer", tc.signBytes)
+			if !tc.allowed {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, pk.VerifySignature(tc.signBytes, sig))
+		})
+	}
+}
+
+func TestSignerDaemonToken(t *testing.T) {
+	c := newTestSignerChain(t)
+	signBytes := testSignDoc(t, c.ChainID, &chantypes.MsgRecvPacket{})
+
+	tests := []struct {
+		name        string
+		daemonToken string
+		token       string
+		allowed     bool
+	}{
+		{"token", testSignerToken, testSignerToken, true},
+		{"wrong token", testSignerToken, "guess", false},
+		{"no token", testSignerToken, "", false},
+		{"daemon without token", "", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(SignerDaemon(tc.daemonToken, c))
+			defer srv.Close()
+
+			signer, err := NewRemoteSigner(srv.URL, c.ChainID, tc.token, c.Encoding.Marshaler)
+			require.NoError(t, err)
+
+			_, pkErr := signer.PubKey("relayer")
+			_, signErr := signer.Sign("relayer", signBytes)
+			if tc.allowed {
+				require.NoError(t, pkErr)
+				require.NoError(t, signErr)
+				return
+			}
+			require.Error(t, pkErr)
+			require.Error(t, signErr)
+		})
+	}
+}
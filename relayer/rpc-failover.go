@@ -0,0 +1,587 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/service"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+var (
+	// RPCHealthCheckInterval is how often the RPC endpoints of a chain with several of them
+	// are checked for the healthiest one
+	RPCHealthCheckInterval = 10 * time.Second
+
+	// RPCMaxHeightLag is how many blocks the active RPC endpoint may fall behind the highest
+	// healthy one before requests are routed away from it
+	RPCMaxHeightLag int64 = 2
+)
+
+// RPCEndpointStatus is the result of the last health check of one of a chain's RPC endpoints
+type RPCEndpointStatus struct {
+	Addr       string `json:"addr" yaml:"addr"`
+	Active     bool   `json:"active" yaml:"active"`
+	Height     int64  `json:"height,omitempty" yaml:"height,omitempty"`
+	CatchingUp bool   `json:"catching-up" yaml:"catching-up"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// rpcEndpoint is one of the RPC endpoints of a chain and the result of its last health check
+type rpcEndpoint struct {
+	addr   string
+	client *rpchttp.HTTP
+
+	height     int64
+	catchingUp bool
+	err        error
+}
+
+func (ep *rpcEndpoint) healthy() bool {
+	return ep.err == nil && !ep.catchingUp
+}
+
+// failoverClient is an RPC client that routes each request to the healthiest of a chain's RPC
+// endpoints, the one with the highest latest height that isn't catching up. The endpoints are
+// checked every RPCHealthCheckInterval, and an endpoint that fails a request is skipped until
+// it passes a check again. Event subscriptions are made through a websocket to the endpoint that
+// was active when the client was started or last reconnected.
+type failoverClient struct {
+	service.BaseService
+
+	endpoints []*rpcEndpoint
+	timeout   time.Duration
+
+	// serializes re-creating the events websocket
+	reconnectMu sync.Mutex
+
+	// guards the health of the endpoints and the fields below
+	mu        sync.Mutex
+	active    int
+	checkedAt time.Time
+	events    *rpchttp.HTTP
+
+	// set to 1 while a health check is running
+	checking int32
+}
+
+var _ rpcclient.RemoteClient = (*failoverClient)(nil)
+
+// newFailoverClient returns a client for the RPC endpoints at addrs, routing requests to the
+// first one until the endpoints are health checked
+func newFailoverClient(addrs []string, timeout time.Duration) (*failoverClient, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no rpc addresses")
+	}
+
+	fc := &failoverClient{timeout: timeout}
+	for _, addr := range addrs {
+		client, err := newRPCClient(addr, timeout)
+		if err != nil {
+			return nil, err
+		}
+		fc.endpoints = append(fc.endpoints, &rpcEndpoint{addr: addr, client: client})
+	}
+	fc.BaseService = *service.NewBaseService(nil, "FailoverClient", fc)
+	return fc, nil
+}
+
+// current returns the endpoint requests are routed to, health checking the endpoints first if
+// the last check is older than RPCHealthCheckInterval
+func (fc *failoverClient) current() *rpcEndpoint {
+	fc.mu.Lock()
+	stale := len(fc.endpoints) > 1 && time.Since(fc.checkedAt) > RPCHealthCheckInterval
+	fc.mu.Unlock()
+
+	if stale {
+		fc.checkHealth()
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.endpoints[fc.active]
+}
+
+// checkHealth queries the status of every endpoint and routes requests to the healthiest one.
+// Requests made while a check is running go to the currently active endpoint.
+func (fc *failoverClient) checkHealth() {
+	if !atomic.CompareAndSwapInt32(&fc.checking, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&fc.checking, 0)
+
+	var wg sync.WaitGroup
+	for _, ep := range fc.endpoints {
+		wg.Add(1)
+		go func(ep *rpcEndpoint) {
+			defer wg.Done()
+			res, err := ep.client.Status(context.Background())
+
+			fc.mu.Lock()
+			defer fc.mu.Unlock()
+			if ep.err = err; err == nil {
+				ep.height, ep.catchingUp = res.SyncInfo.LatestBlockHeight, res.SyncInfo.CatchingUp
+			}
+		}(ep)
+	}
+	wg.Wait()
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.checkedAt = time.Now()
+	fc.route()
+}
+
+// route makes the healthiest endpoint the active one. The active endpoint is kept while it is
+// healthy and no more than RPCMaxHeightLag blocks behind. mu must be held.
+func (fc *failoverClient) route() {
+	best := -1
+	for i, ep := range fc.endpoints {
+		switch {
+		case !ep.healthy():
+		case best == -1, ep.height > fc.endpoints[best].height:
+			best = i
+		}
+	}
+
+	active := fc.endpoints[fc.active]
+	if best == -1 || best == fc.active ||
+		(active.healthy() && active.height+RPCMaxHeightLag >= fc.endpoints[best].height) {
+		return
+	}
+
+	fc.Logger.Info(fmt.Sprintf("- switching rpc endpoint from %s to %s", active.addr, fc.endpoints[best].addr))
+	fc.active = best
+}
+
+// call runs f with the client of the active endpoint. If the endpoint couldn't be reached it is
+// marked unhealthy, so following requests go to another endpoint.
+func (fc *failoverClient) call(f func(client *rpchttp.HTTP) error) error {
+	ep := fc.current()
+	err := f(ep.client)
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && len(fc.endpoints) > 1 {
+		fc.mu.Lock()
+		ep.err = err
+		fc.route()
+		fc.mu.Unlock()
+	}
+	return err
+}
+
+// RPCEndpoints returns the addresses of the chain's RPC endpoints, RPCAddr first
+func (c *Chain) RPCEndpoints() []string {
+	out := []string{c.RPCAddr}
+	seen := map[string]bool{c.RPCAddr: true}
+	for _, addr := range c.RPCAddrs {
+		if !seen[addr] {
+			out = append(out, addr)
+			seen[addr] = true
+		}
+	}
+	return out
+}
+
+// RPCStatus health checks the chain's RPC endpoints and returns their status
+func (c *Chain) RPCStatus() []RPCEndpointStatus {
+	fc, ok := c.Client.(*failoverClient)
+	if !ok {
+		return []RPCEndpointStatus{{Addr: c.RPCAddr, Active: true}}
+	}
+
+	fc.checkHealth()
+	return fc.status()
+}
+
+// newFailoverClient returns a client for the chain's RPC endpoints that logs with the chain's logger
+func (c *Chain) newFailoverClient(timeout time.Duration) (*failoverClient, error) {
+	fc, err := newFailoverClient(c.RPCEndpoints(), timeout)
+	if err != nil {
+		return nil, err
+	}
+	if c.logger != nil {
+		fc.SetLogger(c.logger.With("chain-id", c.ChainID))
+	}
+	return fc, nil
+}
+
+// status returns the result of the last health check of each endpoint
+func (fc *failoverClient) status() []RPCEndpointStatus {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	out := make([]RPCEndpointStatus, 0, len(fc.endpoints))
+	for i, ep := range fc.endpoints {
+		st := RPCEndpointStatus{Addr: ep.addr, Active: i == fc.active, Height: ep.height, CatchingUp: ep.catchingUp}
+		if ep.err != nil {
+			st.Error = ep.err.Error()
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// OnStart implements service.Service by opening the events websocket to the active endpoint
+func (fc *failoverClient) OnStart() error {
+	return fc.connectEvents()
+}
+
+// OnStop implements service.Service
+func (fc *failoverClient) OnStop() {
+	fc.reconnectMu.Lock()
+	defer fc.reconnectMu.Unlock()
+
+	fc.mu.Lock()
+	events := fc.events
+	fc.events = nil
+	fc.mu.Unlock()
+
+	stopEvents(events)
+}
+
+// Reconnect replaces the events websocket with one to the healthiest endpoint. The subscriptions
+// of the old websocket are removed and it is closed, so they must be re-created.
+func (fc *failoverClient) Reconnect() error {
+	if len(fc.endpoints) > 1 {
+		fc.checkHealth()
+	}
+	if !fc.IsRunning() {
+		return fc.Start()
+	}
+	return fc.connectEvents()
+}
+
+// connectEvents opens a websocket to the active endpoint for event subscriptions and closes the
+// previous one
+func (fc *failoverClient) connectEvents() error {
+	fc.reconnectMu.Lock()
+	defer fc.reconnectMu.Unlock()
+
+	client, err := newRPCClient(fc.current().addr, fc.timeout)
+	if err != nil {
+		return err
+	}
+	if err = client.Start(); err != nil {
+		return err
+	}
+
+	fc.mu.Lock()
+	old := fc.events
+	fc.events = client
+	fc.mu.Unlock()
+
+	stopEvents(old)
+	return nil
+}
+
+// stopEvents removes the subscriptions of an events websocket and closes it
+func stopEvents(client *rpchttp.HTTP) {
+	if client == nil || !client.IsRunning() {
+		return
+	}
+
+	// the websocket may be dead already, in which case the subscriptions are gone with it
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = client.UnsubscribeAll(ctx, "")
+	_ = client.Stop()
+}
+
+func (fc *failoverClient) eventsClient() (*rpchttp.HTTP, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if fc.events == nil {
+		return nil, fmt.Errorf("rpc client not started")
+	}
+	return fc.events, nil
+}
+
+// Remote implements rpcclient.RemoteClient
+func (fc *failoverClient) Remote() string {
+	return fc.current().addr
+}
+
+// ABCIInfo implements rpcclient.Client
+func (fc *failoverClient) ABCIInfo(ctx context.Context) (res *ctypes.ResultABCIInfo, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.ABCIInfo(ctx)
+		return
+	})
+	return
+}
+
+// ABCIQuery implements rpcclient.Client
+func (fc *failoverClient) ABCIQuery(ctx context.Context, path string, data bytes.HexBytes) (res *ctypes.ResultABCIQuery, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.ABCIQuery(ctx, path, data)
+		return
+	})
+	return
+}
+
+// ABCIQueryWithOptions implements rpcclient.Client
+func (fc *failoverClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions) (res *ctypes.ResultABCIQuery, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.ABCIQueryWithOptions(ctx, path, data, opts)
+		return
+	})
+	return
+}
+
+// BroadcastTxCommit implements rpcclient.Client
+func (fc *failoverClient) BroadcastTxCommit(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTxCommit, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BroadcastTxCommit(ctx, tx)
+		return
+	})
+	return
+}
+
+// BroadcastTxAsync implements rpcclient.Client
+func (fc *failoverClient) BroadcastTxAsync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BroadcastTxAsync(ctx, tx)
+		return
+	})
+	return
+}
+
+// BroadcastTxSync implements rpcclient.Client
+func (fc *failoverClient) BroadcastTxSync(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultBroadcastTx, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BroadcastTxSync(ctx, tx)
+		return
+	})
+	return
+}
+
+// Subscribe implements rpcclient.Client on the endpoint the client was started on
+func (fc *failoverClient) Subscribe(ctx context.Context, subscriber, query string,
+	outCapacity ...int) (<-chan ctypes.ResultEvent, error) {
+	client, err := fc.eventsClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Subscribe(ctx, subscriber, query, outCapacity...)
+}
+
+// Unsubscribe implements rpcclient.Client
+func (fc *failoverClient) Unsubscribe(ctx context.Context, subscriber, query string) error {
+	client, err := fc.eventsClient()
+	if err != nil {
+		return err
+	}
+	return client.Unsubscribe(ctx, subscriber, query)
+}
+
+// UnsubscribeAll implements rpcclient.Client
+func (fc *failoverClient) UnsubscribeAll(ctx context.Context, subscriber string) error {
+	client, err := fc.eventsClient()
+	if err != nil {
+		return err
+	}
+	return client.UnsubscribeAll(ctx, subscriber)
+}
+
+// Genesis implements rpcclient.Client
+func (fc *failoverClient) Genesis(ctx context.Context) (res *ctypes.ResultGenesis, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Genesis(ctx)
+		return
+	})
+	return
+}
+
+// GenesisChunked implements rpcclient.Client
+func (fc *failoverClient) GenesisChunked(ctx context.Context, id uint) (res *ctypes.ResultGenesisChunk, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.GenesisChunked(ctx, id)
+		return
+	})
+	return
+}
+
+// BlockchainInfo implements rpcclient.Client
+func (fc *failoverClient) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (res *ctypes.ResultBlockchainInfo, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BlockchainInfo(ctx, minHeight, maxHeight)
+		return
+	})
+	return
+}
+
+// NetInfo implements rpcclient.Client
+func (fc *failoverClient) NetInfo(ctx context.Context) (res *ctypes.ResultNetInfo, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.NetInfo(ctx)
+		return
+	})
+	return
+}
+
+// DumpConsensusState implements rpcclient.Client
+func (fc *failoverClient) DumpConsensusState(ctx context.Context) (res *ctypes.ResultDumpConsensusState, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.DumpConsensusState(ctx)
+		return
+	})
+	return
+}
+
+// ConsensusState implements rpcclient.Client
+func (fc *failoverClient) ConsensusState(ctx context.Context) (res *ctypes.ResultConsensusState, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.ConsensusState(ctx)
+		return
+	})
+	return
+}
+
+// ConsensusParams implements rpcclient.Client
+func (fc *failoverClient) ConsensusParams(ctx context.Context, height *int64) (res *ctypes.ResultConsensusParams, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.ConsensusParams(ctx, height)
+		return
+	})
+	return
+}
+
+// Health implements rpcclient.Client
+func (fc *failoverClient) Health(ctx context.Context) (res *ctypes.ResultHealth, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Health(ctx)
+		return
+	})
+	return
+}
+
+// Block implements rpcclient.Client
+func (fc *failoverClient) Block(ctx context.Context, height *int64) (res *ctypes.ResultBlock, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Block(ctx, height)
+		return
+	})
+	return
+}
+
+// BlockByHash implements rpcclient.Client
+func (fc *failoverClient) BlockByHash(ctx context.Context, hash []byte) (res *ctypes.ResultBlock, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BlockByHash(ctx, hash)
+		return
+	})
+	return
+}
+
+// BlockResults implements rpcclient.Client
+func (fc *failoverClient) BlockResults(ctx context.Context, height *int64) (res *ctypes.ResultBlockResults, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BlockResults(ctx, height)
+		return
+	})
+	return
+}
+
+// Commit implements rpcclient.Client
+func (fc *failoverClient) Commit(ctx context.Context, height *int64) (res *ctypes.ResultCommit, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Commit(ctx, height)
+		return
+	})
+	return
+}
+
+// Validators implements rpcclient.Client
+func (fc *failoverClient) Validators(ctx context.Context, height *int64, page, perPage *int) (res *ctypes.ResultValidators, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Validators(ctx, height, page, perPage)
+		return
+	})
+	return
+}
+
+// Tx implements rpcclient.Client
+func (fc *failoverClient) Tx(ctx context.Context, hash []byte, prove bool) (res *ctypes.ResultTx, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Tx(ctx, hash, prove)
+		return
+	})
+	return
+}
+
+// TxSearch implements rpcclient.Client
+func (fc *failoverClient) TxSearch(ctx context.Context, query string, prove bool, page, perPage *int,
+	orderBy string) (res *ctypes.ResultTxSearch, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.TxSearch(ctx, query, prove, page, perPage, orderBy)
+		return
+	})
+	return
+}
+
+// BlockSearch implements rpcclient.Client
+func (fc *failoverClient) BlockSearch(ctx context.Context, query string, page, perPage *int,
+	orderBy string) (res *ctypes.ResultBlockSearch, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BlockSearch(ctx, query, page, perPage, orderBy)
+		return
+	})
+	return
+}
+
+// Status implements rpcclient.Client
+func (fc *failoverClient) Status(ctx context.Context) (res *ctypes.ResultStatus, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.Status(ctx)
+		return
+	})
+	return
+}
+
+// BroadcastEvidence implements rpcclient.Client
+func (fc *failoverClient) BroadcastEvidence(ctx context.Context, ev tmtypes.Evidence) (res *ctypes.ResultBroadcastEvidence, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.BroadcastEvidence(ctx, ev)
+		return
+	})
+	return
+}
+
+// UnconfirmedTxs implements rpcclient.Client
+func (fc *failoverClient) UnconfirmedTxs(ctx context.Context, limit *int) (res *ctypes.ResultUnconfirmedTxs, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.UnconfirmedTxs(ctx, limit)
+		return
+	})
+	return
+}
+
+// NumUnconfirmedTxs implements rpcclient.Client
+func (fc *failoverClient) NumUnconfirmedTxs(ctx context.Context) (res *ctypes.ResultUnconfirmedTxs, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.NumUnconfirmedTxs(ctx)
+		return
+	})
+	return
+}
+
+// CheckTx implements rpcclient.Client
+func (fc *failoverClient) CheckTx(ctx context.Context, tx tmtypes.Tx) (res *ctypes.ResultCheckTx, err error) {
+	err = fc.call(func(client *rpchttp.HTTP) (err error) {
+		res, err = client.CheckTx(ctx, tx)
+		return
+	})
+	return
+}
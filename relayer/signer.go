@@ -0,0 +1,131 @@
+package relayer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	sdkCtx "github.com/cosmos/cosmos-sdk/client"
+	tx "github.com/cosmos/cosmos-sdk/client/tx"
+	keys "github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+// Signer holds the keys a chain's transactions are signed with. The private keys never leave
+// the signer, so they can be kept off the relay host by a remote signer.
+type Signer interface {
+	// PubKey returns the public key of the named key
+	PubKey(name string) (cryptotypes.PubKey, error)
+
+	// Sign signs msg with the named key
+	Sign(name string, msg []byte) ([]byte, error)
+}
+
+// keyringSigner signs with the keys of a local keyring
+type keyringSigner struct {
+	kr keys.Keyring
+}
+
+// NewKeyringSigner returns a Signer that signs with the keys of kr
+func NewKeyringSigner(kr keys.Keyring) Signer {
+	return keyringSigner{kr: kr}
+}
+
+// PubKey implements Signer
+func (ks keyringSigner) PubKey(name string) (cryptotypes.PubKey, error) {
+	info, err := ks.kr.Key(name)
+	if err != nil {
+		return nil, err
+	}
+	return info.GetPubKey(), nil
+}
+
+// Sign implements Signer
+func (ks keyringSigner) Sign(name string, msg []byte) ([]byte, error) {
+	sig, _, err := ks.kr.Sign(name, msg)
+	return sig, err
+}
+
+// newSigner returns the remote signer of the chain if it has one configured, a signer for the
+// local keyring otherwise
+func (c *Chain) newSigner(kr keys.Keyring) (Signer, error) {
+	if c.Signer == "" {
+		return NewKeyringSigner(kr), nil
+	}
+	token, err := c.signerToken()
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSigner(c.Signer, c.ChainID, token, c.Encoding.Marshaler)
+}
+
+// signerToken returns the token the chain authenticates to its signer daemon with, read from its
+// signer token file
+func (c *Chain) signerToken() (string, error) {
+	if c.SignerTokenFile == "" {
+		return "", fmt.Errorf("chain %s has a signer but no signer-token-file", c.ChainID)
+	}
+	return ReadSignerToken(c.SignerTokenFile)
+}
+
+// ReadSignerToken returns the signer daemon token in file, which must not be empty
+func ReadSignerToken(file string) (string, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read signer token: %w", err)
+	}
+	token := strings.TrimSpace(string(bz))
+	if token == "" {
+		return "", fmt.Errorf("signer token file %s is empty", file)
+	}
+	return token, nil
+}
+
+// KeyAddress returns the address of the named key of the chain
+func (c *Chain) KeyAddress(name string) (sdk.AccAddress, error) {
+	pk, err := c.signer.PubKey(name)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(pk.Address()), nil
+}
+
+// signTx signs the transaction in txb with the chain's key through its signer, using the account
+// number and sequence of txf
+func (c *Chain) signTx(txf tx.Factory, txb sdkCtx.TxBuilder) error {
+	pk, err := c.signer.PubKey(c.Key)
+	if err != nil {
+		return err
+	}
+
+	signMode := txf.SignMode()
+	if signMode == signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		signMode = c.Encoding.TxConfig.SignModeHandler().DefaultMode()
+	}
+
+	// the signer infos are part of the signed bytes, so they're set with an empty signature first
+	sigData := signing.SingleSignatureData{SignMode: signMode}
+	sig := signing.SignatureV2{PubKey: pk, Data: &sigData, Sequence: txf.Sequence()}
+	if err = txb.SetSignatures(sig); err != nil {
+		return err
+	}
+
+	signerData := authsigning.SignerData{
+		ChainID:       c.ChainID,
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+	}
+	bz, err := c.Encoding.TxConfig.SignModeHandler().GetSignBytes(signMode, signerData, txb.GetTx())
+	if err != nil {
+		return err
+	}
+
+	sigData.Signature, err = c.signer.Sign(c.Key, bz)
+	if err != nil {
+		return fmt.Errorf("failed to sign tx with key %s of chain %s: %w", c.Key, c.ChainID, err)
+	}
+	return txb.SetSignatures(sig)
+}
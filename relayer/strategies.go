@@ -2,10 +2,13 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-	tmservice "github.com/tendermint/tendermint/libs/service"
-	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	tmtypes "github.com/tendermint/tendermint/types"
 )
 
@@ -17,11 +20,66 @@ var (
 // Strategy defines
 type Strategy interface {
 	GetType() string
-	HandleEvents(src, dst *Chain, srch, dsth int64, events map[string][]string)
-	UnrelayedSequences(src, dst *Chain) (*RelaySequences, error)
-	UnrelayedAcknowledgements(src, dst *Chain) (*RelaySequences, error)
-	RelayPackets(src, dst *Chain, sp *RelaySequences) error
-	RelayAcknowledgements(src, dst *Chain, sp *RelaySequences) error
+	HandleEvents(ctx context.Context, src, dst *Chain, srch, dsth int64, events map[string][]string)
+	UnrelayedSequences(ctx context.Context, src, dst *Chain) (*RelaySequences, error)
+	UnrelayedAcknowledgements(ctx context.Context, src, dst *Chain) (*RelaySequences, error)
+	RelayPackets(ctx context.Context, src, dst *Chain, sp *RelaySequences) error
+	RelayAcknowledgements(ctx context.Context, src, dst *Chain, sp *RelaySequences) error
+}
+
+// FilteredStrategy is implemented by strategies that support a path's packet filter
+type FilteredStrategy interface {
+	SetPacketFilter(filter *PacketFilter)
+}
+
+// DeadLetterStrategy is implemented by strategies that store packets that failed to relay
+// in a FailedPacketQueue. The queue of a running strategy is retried periodically.
+type DeadLetterStrategy interface {
+	SetFailedPacketQueue(q *FailedPacketQueue)
+	GetFailedPacketQueue() *FailedPacketQueue
+}
+
+// BlockTimeStrategy is implemented by strategies that check packet timeouts against the block
+// times of the chains. The event listener passes it the header time of every new block.
+type BlockTimeStrategy interface {
+	SetBlockTime(chainID string, t time.Time)
+}
+
+// StrategyFactory builds a Strategy from the options set in a path's strategy config
+type StrategyFactory func(options map[string]interface{}) (Strategy, error)
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = make(map[string]StrategyFactory)
+)
+
+// RegisterStrategy makes a strategy available to paths under the given name. It is meant
+// to be called from the init function of the package implementing the strategy and panics
+// if the factory is nil or a strategy with the same name is already registered.
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	if factory == nil {
+		panic(fmt.Sprintf("strategy factory for %s is nil", name))
+	}
+	if _, ok := strategies[name]; ok {
+		panic(fmt.Sprintf("strategy %s is already registered", name))
+	}
+	strategies[name] = factory
+}
+
+// RegisteredStrategies returns the sorted names of all registered strategies
+func RegisteredStrategies() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // MustGetStrategy returns the strategy and panics on error
@@ -34,24 +92,61 @@ func (p *Path) MustGetStrategy() Strategy {
 	return strategy
 }
 
-// GetStrategy the strategy defined in the relay messages
+// GetStrategy builds the strategy defined in the path with its options
 func (p *Path) GetStrategy() (Strategy, error) {
-	switch p.Strategy.Type {
-	case (&NaiveStrategy{}).GetType():
-		return &NaiveStrategy{}, nil
-	default:
-		return nil, fmt.Errorf("invalid strategy: %s", p.Strategy.Type)
+	strategiesMu.RLock()
+	factory, ok := strategies[p.Strategy.Type]
+	strategiesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("invalid strategy: %s, registered strategies: %s",
+			p.Strategy.Type, strings.Join(RegisteredStrategies(), ", "))
 	}
+
+	strategy, err := factory(p.Strategy.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs, ok := strategy.(FilteredStrategy); ok {
+		fs.SetPacketFilter(p.Filter)
+	} else if !p.Filter.Empty() {
+		return nil, fmt.Errorf("strategy %s does not support packet filters", p.Strategy.Type)
+	}
+
+	return strategy, nil
 }
 
-// StrategyCfg defines which relaying strategy to take for a given path
+// StrategyCfg defines which relaying strategy to take for a given path along with
+// any options specific to that strategy
 type StrategyCfg struct {
-	Type string `json:"type" yaml:"type"`
+	Type    string                 `json:"type" yaml:"type"`
+	Options map[string]interface{} `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// RunStrategy runs a given strategy until ctx is done. If sweepInterval is non-zero, unrelayed
+// packets and acknowledgements are periodically queried and relayed to recover from missed events.
+// It returns once the strategy is started, anything that piled up while the relayer was down is
+// relayed in the background.
+//
+// Once ctx is done no new events are handled. The returned function waits for relays that are
+// still in flight to complete. If the context passed to it is done first, the in-flight relays
+// are cancelled and its error is returned.
+func RunStrategy(ctx context.Context, src, dst *Chain, strategy Strategy,
+	sweepInterval time.Duration) (func(context.Context) error, error) {
+	return NewEventHub(src, dst).RunStrategy(ctx, src, dst, strategy, sweepInterval)
 }
 
-// RunStrategy runs a given strategy
-func RunStrategy(src, dst *Chain, strategy Strategy) (func(), error) {
-	doneChan := make(chan struct{})
+// RunStrategy runs a given strategy like the package level RunStrategy, receiving chain events
+// through the hub. Several paths can be run on the same hub with the same ctx, each path needs
+// its own src and dst chains and strategy.
+func (h *EventHub) RunStrategy(ctx context.Context, src, dst *Chain, strategy Strategy,
+	sweepInterval time.Duration) (func(context.Context) error, error) {
+	var (
+		wg sync.WaitGroup
+
+		// relays get their own context so they aren't cut off mid-broadcast when ctx is done
+		relayCtx, cancelRelays = context.WithCancel(context.Background())
+	)
 
 	// Fetch latest headers for each chain and store them in sync headers
 	// _, _, err := UpdateLightClients(src, dst)
@@ -59,101 +154,201 @@ func RunStrategy(src, dst *Chain, strategy Strategy) (func(), error) {
 	// 	return nil, err
 	// }
 
-	// Next start the goroutine that listens to each chain for block and tx events
-	go relayerListenLoop(src, dst, doneChan, strategy)
+	// Start the goroutine that relays anything that piled up while the relayer was down and
+	// then listens to each chain for block and tx events. It runs in the background so other
+	// paths start right away.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		relayerListenLoop(ctx, relayCtx, &wg, h, src, dst, strategy)
+	}()
 
-	// Fetch any unrelayed sequences depending on the channel order
-	sp, err := strategy.UnrelayedSequences(src, dst)
-	if err != nil {
-		return nil, err
+	// Start the goroutine that periodically relays anything the event listener missed. A sweep
+	// or retry that overlaps with the startup relay skips the packets it is relaying, the
+	// strategy claims each packet for one relay at a time.
+	if sweepInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			relayerSweepLoop(ctx, relayCtx, src, dst, strategy, sweepInterval)
+		}()
 	}
 
-	if err = strategy.RelayPackets(src, dst, sp); err != nil {
-		return nil, err
+	// Start the goroutine that retries packets that failed to relay
+	if ds, ok := strategy.(DeadLetterStrategy); ok && ds.GetFailedPacketQueue() != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			relayerRetryLoop(ctx, relayCtx, src, dst, strategy, ds.GetFailedPacketQueue())
+		}()
 	}
 
-	// Return a function to stop the relayer goroutine
-	return func() { doneChan <- struct{}{} }, nil
+	// Return a function to wait for the relayer goroutines to finish
+	return func(drainCtx context.Context) error {
+		defer cancelRelays()
+
+		drained := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(drained)
+		}()
+
+		select {
+		case <-drained:
+			return nil
+		case <-drainCtx.Done():
+			return drainCtx.Err()
+		}
+	}, nil
 }
 
-func relayerListenLoop(src, dst *Chain, doneChan chan struct{}, strategy Strategy) {
-	var (
-		srcTxEvents, srcBlockEvents, dstTxEvents, dstBlockEvents <-chan ctypes.ResultEvent
-		srcTxCancel, srcBlockCancel, dstTxCancel, dstBlockCancel context.CancelFunc
-		err                                                      error
-	)
+// relayerSweepLoop queries for unrelayed packets and acknowledgements between src and dst
+// on every tick of the given interval and relays whatever it finds. Events that were dropped
+// by the listener (e.g. closed subscription, failed retries, node restarts) are picked up here.
+func relayerSweepLoop(ctx, relayCtx context.Context, src, dst *Chain, strategy Strategy, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	// Start client for source chain
-	if err = src.Start(); err != nil {
-		if err != tmservice.ErrAlreadyStarted {
-			src.Error(err)
+	src.Log(fmt.Sprintf("- sweeping for unrelayed packets between [%s]port{%s} and [%s]port{%s} every %s",
+		src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID, interval))
+
+	for {
+		select {
+		case <-ticker.C:
+			packets, acks, err := relayUnrelayed(relayCtx, src, dst, strategy)
+			if err != nil {
+				src.Error(err)
+				continue
+			}
+			if packets > 0 || acks > 0 {
+				logRelayedOnSweep(src, dst, packets, acks)
+			}
+		case <-ctx.Done():
 			return
 		}
 	}
+}
 
-	// Subscibe to txEvents from the source chain
-	if srcTxEvents, srcTxCancel, err = src.Subscribe(txEvents); err != nil {
-		src.Error(err)
-		return
+// relayUnrelayed relays all outstanding packets and acknowledgements between src and dst
+// in both directions and returns the number of packets and acknowledgements relayed
+func relayUnrelayed(ctx context.Context, src, dst *Chain, strategy Strategy) (packets, acks int, err error) {
+	sp, err := strategy.UnrelayedSequences(ctx, src, dst)
+	if err != nil {
+		return 0, 0, err
 	}
-	defer srcTxCancel()
-	src.Log(fmt.Sprintf("- listening to tx events from %s...", src.ChainID))
 
-	// Subscibe to blockEvents from the source chain
-	if srcBlockEvents, srcBlockCancel, err = src.Subscribe(blEvents); err != nil {
-		src.Error(err)
-		return
+	if len(sp.Src) > 0 || len(sp.Dst) > 0 {
+		if err = strategy.RelayPackets(ctx, src, dst, sp); err != nil {
+			return 0, 0, queueNotCommitted(FailedPacketKindPacket, src, dst, strategy, sp, err)
+		}
+		packets = len(sp.Src) + len(sp.Dst)
 	}
-	defer srcBlockCancel()
-	src.Log(fmt.Sprintf("- listening to block events from %s...", src.ChainID))
 
-	// Subscribe to destination chain
-	if err = dst.Start(); err != nil {
-		if err != tmservice.ErrAlreadyStarted {
-			dst.Error(err)
-			return
+	ap, err := strategy.UnrelayedAcknowledgements(ctx, src, dst)
+	if err != nil {
+		return packets, 0, err
+	}
+
+	if len(ap.Src) > 0 || len(ap.Dst) > 0 {
+		if err = strategy.RelayAcknowledgements(ctx, src, dst, ap); err != nil {
+			return packets, 0, queueNotCommitted(FailedPacketKindAck, src, dst, strategy, ap, err)
 		}
+		acks = len(ap.Src) + len(ap.Dst)
 	}
 
-	// Subscibe to txEvents from the destination chain
-	if dstTxEvents, dstTxCancel, err = dst.Subscribe(txEvents); err != nil {
-		dst.Error(err)
-		return
+	return packets, acks, nil
+}
+
+// queueNotCommitted adds the sequences of a relay with a batch that was broadcast but never
+// committed to the strategy's failed packet queue, so they are retried with backoff and reach
+// the dead letter queue if they keep failing. It returns relayErr.
+func queueNotCommitted(kind string, src, dst *Chain, strategy Strategy, sp *RelaySequences, relayErr error) error {
+	dls, ok := strategy.(DeadLetterStrategy)
+	if !ok || dls.GetFailedPacketQueue() == nil || !errors.Is(relayErr, ErrTxNotCommitted) {
+		return relayErr
 	}
-	defer dstTxCancel()
-	dst.Log(fmt.Sprintf("- listening to tx events from %s...", dst.ChainID))
+	return dls.GetFailedPacketQueue().recordRetryFailure(kind, src, dst, sp, relayErr)
+}
+
+// relayerListenLoop relays the packets and acknowledgements that are outstanding between src and
+// dst and then handles events from them until ctx is done. Events are handled with relayCtx in
+// goroutines tracked by wg.
+func relayerListenLoop(ctx, relayCtx context.Context, wg *sync.WaitGroup, hub *EventHub,
+	src, dst *Chain, strategy Strategy) {
+	// Listen to tx and block events on both chains, the subscriptions are shared with
+	// any other path on the hub and re-created whenever they drop
+	var (
+		srcSub = hub.listen(ctx.Done(), src)
+		dstSub = hub.listen(ctx.Done(), dst)
+	)
 
-	// Subscibe to blockEvents from the destination chain
-	if dstBlockEvents, dstBlockCancel, err = dst.Subscribe(blEvents); err != nil {
+	// Clear any packets and acknowledgements that piled up while the relayer was down before
+	// handling events. The events of packets sent in the meantime are buffered by the
+	// subscriptions. Anything that fails here is picked up by the next sweep or retry.
+	packets, acks, err := relayUnrelayed(relayCtx, src, dst, strategy)
+	if err != nil {
 		src.Error(err)
-		return
 	}
-	defer dstBlockCancel()
-	dst.Log(fmt.Sprintf("- listening to block events from %s...", dst.ChainID))
+	logRelayedOnStartup(src, dst, packets, acks)
+
+	// handle runs f in a goroutine tracked by wg
+	handle := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
 
 	// Listen to channels and take appropriate action
 	var srch, dsth int64
 	for {
 		select {
-		case srcMsg := <-srcTxEvents:
+		case srcMsg := <-srcSub.txEvents:
 			src.logTx(srcMsg.Events)
-			go strategy.HandleEvents(dst, src, dsth, srch, srcMsg.Events)
-		case dstMsg := <-dstTxEvents:
+			h1, h2 := dsth, srch
+			handle(func() { strategy.HandleEvents(relayCtx, dst, src, h1, h2, srcMsg.Events) })
+		case dstMsg := <-dstSub.txEvents:
 			dst.logTx(dstMsg.Events)
-			go strategy.HandleEvents(src, dst, srch, dsth, dstMsg.Events)
-		case srcMsg := <-srcBlockEvents:
+			h1, h2 := srch, dsth
+			handle(func() { strategy.HandleEvents(relayCtx, src, dst, h1, h2, dstMsg.Events) })
+		case srcMsg := <-srcSub.blockEvents:
 			bl, _ := srcMsg.Data.(tmtypes.EventDataNewBlock)
 			srch = bl.Block.Height
-			go strategy.HandleEvents(dst, src, dsth, srch, srcMsg.Events)
-		case dstMsg := <-dstBlockEvents:
+			setBlockTime(strategy, src, bl)
+			h1, h2 := dsth, srch
+			handle(func() { strategy.HandleEvents(relayCtx, dst, src, h1, h2, srcMsg.Events) })
+		case dstMsg := <-dstSub.blockEvents:
 			bl, _ := dstMsg.Data.(tmtypes.EventDataNewBlock)
 			dsth = bl.Block.Height
-			go strategy.HandleEvents(src, dst, srch, dsth, dstMsg.Events)
-		case <-doneChan:
+			setBlockTime(strategy, dst, bl)
+			h1, h2 := srch, dsth
+			handle(func() { strategy.HandleEvents(relayCtx, src, dst, h1, h2, dstMsg.Events) })
+		case <-srcSub.reconnected:
+			handle(func() { catchUpAfterReconnect(relayCtx, src, dst, strategy) })
+		case <-dstSub.reconnected:
+			handle(func() { catchUpAfterReconnect(relayCtx, src, dst, strategy) })
+		case <-ctx.Done():
 			src.Log(fmt.Sprintf("- [%s]:{%s} <-> [%s]:{%s} relayer shutting down",
 				src.ChainID, src.PathEnd.PortID, dst.ChainID, dst.PathEnd.PortID))
-			close(doneChan)
 			return
 		}
 	}
 }
+
+// setBlockTime passes the header time of a new block of c to strategies that track block times
+func setBlockTime(strategy Strategy, c *Chain, bl tmtypes.EventDataNewBlock) {
+	if bs, ok := strategy.(BlockTimeStrategy); ok {
+		bs.SetBlockTime(c.ChainID, bl.Block.Time)
+	}
+}
+
+// catchUpAfterReconnect relays anything that was missed while a chain's subscriptions were down
+func catchUpAfterReconnect(ctx context.Context, src, dst *Chain, strategy Strategy) {
+	packets, acks, err := relayUnrelayed(ctx, src, dst, strategy)
+	if err != nil {
+		src.Error(err)
+		return
+	}
+	logRelayedOnReconnect(src, dst, packets, acks)
+}
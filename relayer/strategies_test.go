@@ -0,0 +1,78 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testDeadLetterStrategy is a testRetryStrategy with a failed packet queue
+type testDeadLetterStrategy struct {
+	*testRetryStrategy
+	q *FailedPacketQueue
+}
+
+func (s *testDeadLetterStrategy) SetFailedPacketQueue(q *FailedPacketQueue) {
+	s.q = q
+}
+
+func (s *testDeadLetterStrategy) GetFailedPacketQueue() *FailedPacketQueue {
+	return s.q
+}
+
+func TestRelayUnrelayedQueuesNotCommitted(t *testing.T) {
+	var (
+		src          = &Chain{ChainID: "ibc-0"}
+		dst          = &Chain{ChainID: "ibc-1"}
+		notCommitted = (&RelayMsgs{NotCommitted: true}).Err()
+	)
+
+	tests := []struct {
+		name                     string
+		unrelayed, unrelayedAcks *RelaySequences
+		relayErr                 error
+
+		// the kind of the queued sequences, if any
+		queued string
+	}{
+		{"packets not committed", &RelaySequences{Src: []uint64{1, 2}}, &RelaySequences{},
+			notCommitted, FailedPacketKindPacket},
+		{"acks not committed", &RelaySequences{}, &RelaySequences{Src: []uint64{1, 2}},
+			notCommitted, FailedPacketKindAck},
+		{"acks failed", &RelaySequences{}, &RelaySequences{Src: []uint64{1, 2}},
+			(&RelayMsgs{}).Err(), ""},
+		{"acks relayed", &RelaySequences{}, &RelaySequences{Src: []uint64{1, 2}}, nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			strategy := &testDeadLetterStrategy{testRetryStrategy: &testRetryStrategy{
+				unrelayed:     tc.unrelayed,
+				unrelayedAcks: tc.unrelayedAcks,
+				err:           tc.relayErr,
+			}}
+			strategy.SetFailedPacketQueue(NewFailedPacketQueue(t.TempDir(), "demo"))
+
+			_, _, err := relayUnrelayed(context.Background(), src, dst, strategy)
+			if tc.relayErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, errors.Is(tc.relayErr, ErrTxNotCommitted), errors.Is(err, ErrTxNotCommitted))
+			}
+
+			fps, err := strategy.q.List()
+			require.NoError(t, err)
+			if tc.queued == "" {
+				require.Empty(t, fps)
+				return
+			}
+			require.Len(t, fps, 2)
+			for i, fp := range fps {
+				require.Equal(t, tc.queued, fp.Kind)
+				require.Equal(t, uint64(i+1), fp.Sequence)
+				require.Equal(t, direction(src, dst), fp.Direction)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package relayer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tmservice "github.com/tendermint/tendermint/libs/service"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var (
+	// SubscriptionStaleTimeout is the amount of time without a block event after which
+	// a chain's subscriptions are considered dead and are re-created
+	SubscriptionStaleTimeout = time.Minute
+
+	// Bounds of the exponential backoff used when re-creating subscriptions
+	resubscribeMinDelay = time.Second
+	resubscribeMaxDelay = time.Minute
+
+	// listenerBufferSize is how many events of each kind a listener can fall behind before
+	// events are dropped for it
+	listenerBufferSize = 1000
+)
+
+// EventHub shares a single tx and block event subscription per chain between all the
+// paths relaying over it
+type EventHub struct {
+	mu   sync.Mutex
+	subs map[string]*chainSubscription
+}
+
+// NewEventHub returns an EventHub that subscribes to events through the given chains. Any
+// other chain is subscribed to through the first path that relays over it.
+func NewEventHub(chains ...*Chain) *EventHub {
+	h := &EventHub{subs: make(map[string]*chainSubscription)}
+	for _, c := range chains {
+		h.subs[c.ChainID] = newChainSubscription(c)
+	}
+	return h
+}
+
+// listen returns a listener for the events of chain c until done is closed. The chain is
+// subscribed to on the first call for its chain ID, and the subscription is kept until the
+// done channel of that call is closed.
+func (h *EventHub) listen(done <-chan struct{}, c *Chain) *chainListener {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub, ok := h.subs[c.ChainID]
+	if !ok {
+		sub = newChainSubscription(c)
+		h.subs[c.ChainID] = sub
+	}
+
+	l := sub.listen(done)
+	sub.start.Do(func() { go sub.run(done) })
+	return l
+}
+
+// chainSubscription maintains the tx and block event subscriptions to a chain and forwards
+// events to each of its listeners. Whenever the underlying subscriptions are closed or go
+// silent they are re-created with exponential backoff and the listeners are signalled.
+type chainSubscription struct {
+	chain *Chain
+	start sync.Once
+
+	mu        sync.Mutex
+	listeners []*chainListener
+}
+
+// chainListener receives the events of a chainSubscription. Its channels stay open across
+// reconnects and a signal is sent on reconnected whenever the subscription is re-created.
+// Events are dropped for a listener whose channel is full, the sweep of its path picks up
+// whatever it missed.
+type chainListener struct {
+	done <-chan struct{}
+
+	txEvents    chan ctypes.ResultEvent
+	blockEvents chan ctypes.ResultEvent
+	reconnected chan struct{}
+}
+
+func newChainSubscription(c *Chain) *chainSubscription {
+	return &chainSubscription{chain: c}
+}
+
+// listen adds a listener that receives events until done is closed
+func (s *chainSubscription) listen(done <-chan struct{}) *chainListener {
+	l := &chainListener{
+		done:        done,
+		txEvents:    make(chan ctypes.ResultEvent, listenerBufferSize),
+		blockEvents: make(chan ctypes.ResultEvent, listenerBufferSize),
+		reconnected: make(chan struct{}, 1),
+	}
+
+	s.mu.Lock()
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+	return l
+}
+
+// getListeners returns the current listeners of the subscription
+func (s *chainSubscription) getListeners() []*chainListener {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*chainListener(nil), s.listeners...)
+}
+
+// dispatch sends ev on the channel returned by ch for every listener that is still listening,
+// without waiting on a listener that fell behind, so it doesn't hold up the others
+func (s *chainSubscription) dispatch(ev ctypes.ResultEvent, ch func(*chainListener) chan ctypes.ResultEvent) {
+	for _, l := range s.getListeners() {
+		select {
+		case <-l.done:
+			continue
+		default:
+		}
+
+		select {
+		case ch(l) <- ev:
+		default:
+			s.chain.Error(fmt.Errorf("listener fell behind, dropped %s event", ev.Query))
+		}
+	}
+}
+
+// run subscribes to the chain and forwards events until done is closed
+func (s *chainSubscription) run(done <-chan struct{}) {
+	c := s.chain
+	defer c.setSubscriptionLive(false)
+
+	first := true
+	for {
+		txCh, blockCh, ok := s.subscribe(done, !first)
+		if !ok {
+			return
+		}
+
+		c.setSubscriptionLive(true)
+		if !first {
+			c.Log(fmt.Sprintf("- [%s] resubscribed to tx and block events", c.ChainID))
+			for _, l := range s.getListeners() {
+				select {
+				case l.reconnected <- struct{}{}:
+				default:
+				}
+			}
+		}
+		first = false
+
+		if !s.forward(done, txCh, blockCh) {
+			return
+		}
+
+		c.setSubscriptionLive(false)
+	}
+}
+
+// subscribe creates the tx and block subscriptions, retrying with exponential backoff until
+// it succeeds or done is closed. If reconnect is set the chain's events websocket is
+// re-created first.
+func (s *chainSubscription) subscribe(done <-chan struct{},
+	reconnect bool) (txCh, blockCh <-chan ctypes.ResultEvent, ok bool) {
+	c := s.chain
+	delay := resubscribeMinDelay
+	for {
+		var err error
+		if txCh, blockCh, err = c.subscribeEvents(reconnect); err == nil {
+			return txCh, blockCh, true
+		}
+
+		c.Error(fmt.Errorf("failed to subscribe to events, retrying in %s: %w", delay, err))
+		select {
+		case <-time.After(delay):
+		case <-done:
+			return nil, nil, false
+		}
+
+		// always re-create the websocket after a failed attempt
+		reconnect = true
+		if delay *= 2; delay > resubscribeMaxDelay {
+			delay = resubscribeMaxDelay
+		}
+	}
+}
+
+// forward passes events through to the subscription's listeners. It returns false if
+// done was closed and true if the subscriptions were closed or went stale.
+func (s *chainSubscription) forward(done <-chan struct{},
+	txCh, blockCh <-chan ctypes.ResultEvent) bool {
+	c := s.chain
+	stale := time.NewTimer(SubscriptionStaleTimeout)
+	defer stale.Stop()
+
+	for {
+		select {
+		case ev, ok := <-txCh:
+			if !ok {
+				c.Error(fmt.Errorf("tx event subscription closed"))
+				return true
+			}
+			s.dispatch(ev, func(l *chainListener) chan ctypes.ResultEvent { return l.txEvents })
+		case ev, ok := <-blockCh:
+			if !ok {
+				c.Error(fmt.Errorf("block event subscription closed"))
+				return true
+			}
+			if !stale.Stop() {
+				<-stale.C
+			}
+			stale.Reset(SubscriptionStaleTimeout)
+			s.dispatch(ev, func(l *chainListener) chan ctypes.ResultEvent { return l.blockEvents })
+		case <-stale.C:
+			c.Error(fmt.Errorf("no block events received for %s", SubscriptionStaleTimeout))
+			return true
+		case <-done:
+			return false
+		}
+	}
+}
+
+// subscribeEvents starts the chain's client, or re-creates its events websocket if reconnect
+// is set, and subscribes to tx and block events
+func (c *Chain) subscribeEvents(reconnect bool) (txCh, blockCh <-chan ctypes.ResultEvent, err error) {
+	if reconnect {
+		err = c.Reconnect()
+	} else if err = c.Start(); err == tmservice.ErrAlreadyStarted {
+		err = nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txCh, txCancel, err := c.Subscribe(txEvents)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer txCancel()
+	c.Log(fmt.Sprintf("- listening to tx events from %s...", c.ChainID))
+
+	blockCh, blockCancel, err := c.Subscribe(blEvents)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer blockCancel()
+	c.Log(fmt.Sprintf("- listening to block events from %s...", c.ChainID))
+
+	return txCh, blockCh, nil
+}
+
+// Reconnect re-creates the websocket the chain's RPC client subscribes to events through, on the
+// healthiest endpoint. The client itself is kept, so copies of the chain keep sharing it. Any
+// existing subscriptions are removed and must be re-created.
+func (c *Chain) Reconnect() error {
+	fc, ok := c.Client.(*failoverClient)
+	if !ok {
+		return fmt.Errorf("rpc client of chain %s can't reconnect", c.ChainID)
+	}
+	return fc.Reconnect()
+}
+
+// SubscriptionLive returns true if the relayer currently holds live event subscriptions
+// to the chain
+func (c *Chain) SubscriptionLive() bool {
+	return atomic.LoadInt32(&c.subscribed) == 1
+}
+
+func (c *Chain) setSubscriptionLive(live bool) {
+	var v int32
+	if live {
+		v = 1
+	}
+	atomic.StoreInt32(&c.subscribed, v)
+}
@@ -0,0 +1,136 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	retry "github.com/avast/retry-go"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+	committypes "github.com/cosmos/ibc-go/v2/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v2/modules/core/24-host"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	provtypes "github.com/tendermint/tendermint/light/provider"
+)
+
+// ErrQueryVerification is returned when a query response of a chain with verify-queries set
+// doesn't match the app hash of the chain's light blocks
+var ErrQueryVerification = errors.New("query verification failed")
+
+// verifiedAppHash returns the app hash of the light block at height, after verifying the block
+// from the trusted light blocks of the chain's light client database. Nothing can be verified
+// until the database is initialized.
+func (c *Chain) verifiedAppHash(height int64) ([]byte, error) {
+	lp, err := c.lightProvider()
+	if err != nil {
+		return nil, err
+	}
+	if !lp.initialized() {
+		return nil, fmt.Errorf("can't verify queries: %w", lp.errNotInitialized())
+	}
+
+	// the light block after a query of the latest state may not be committed yet, the light
+	// client would drop a primary that doesn't have it
+	if err = retry.Do(func() error {
+		_, err := lp.primary.LightBlock(context.Background(), height)
+		return err
+	}, RtyAtt, RtyDel, RtyErr, retry.RetryIf(func(err error) bool {
+		return errors.Is(err, provtypes.ErrHeightTooHigh)
+	})); err != nil {
+		return nil, fmt.Errorf("failed to get light block %d of chain %s: %w", height, c.ChainID, err)
+	}
+
+	lb, err := lp.verifiedLightBlock(context.Background(), height)
+	if err != nil {
+		return nil, fmt.Errorf("%w: light block %d of chain %s can't be verified from its trusted light blocks: %v",
+			ErrQueryVerification, height, c.ChainID, err)
+	}
+	return lb.AppHash, nil
+}
+
+// verifyABCIResponse checks the proof ops of a response to a /store/<store>/key query against
+// the app hash of the light block after the queried height. An empty value must be proven
+// absent. It does nothing unless the chain has verify-queries set.
+func (c *Chain) verifyABCIResponse(path string, res abci.ResponseQuery) error {
+	if !c.VerifyQueries {
+		return nil
+	}
+
+	if res.ProofOps == nil {
+		return fmt.Errorf("%w: %s on chain %s has no proof", ErrQueryVerification, path, c.ChainID)
+	}
+
+	appHash, err := c.verifiedAppHash(res.Height + 1)
+	if err != nil {
+		return err
+	}
+
+	store := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)[1]
+	kp := merkle.KeyPath{}.
+		AppendKey([]byte(store), merkle.KeyEncodingURL).
+		AppendKey(res.Key, merkle.KeyEncodingURL)
+
+	prt := rootmulti.DefaultProofRuntime()
+	if len(res.Value) == 0 {
+		err = prt.VerifyAbsence(res.ProofOps, appHash, kp.String())
+	} else {
+		err = prt.VerifyValue(res.ProofOps, appHash, kp.String(), res.Value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s of key %X at height %d on chain %s: %v",
+			ErrQueryVerification, path, res.Key, res.Height, c.ChainID, err)
+	}
+	return nil
+}
+
+// verifyIBCProof checks that proof, as forwarded to the counterparty chain, proves value under
+// key in the IBC store at proofHeight, or the key's absence if value is empty. It does nothing
+// unless the chain has verify-queries set.
+func (c *Chain) verifyIBCProof(key, value, proof []byte, proofHeight clienttypes.Height) error {
+	if !c.VerifyQueries {
+		return nil
+	}
+
+	var merkleProof committypes.MerkleProof
+	if err := c.Encoding.Marshaler.Unmarshal(proof, &merkleProof); err != nil {
+		return fmt.Errorf("%w: failed to decode proof of %s on chain %s: %v", ErrQueryVerification, key, c.ChainID, err)
+	}
+
+	appHash, err := c.verifiedAppHash(int64(proofHeight.RevisionHeight))
+	if err != nil {
+		return err
+	}
+
+	var (
+		root  = committypes.NewMerkleRoot(appHash)
+		path  = committypes.NewMerklePath(host.StoreKey, string(key))
+		specs = committypes.GetSDKSpecs()
+	)
+	if len(value) == 0 {
+		err = merkleProof.VerifyNonMembership(specs, root, path)
+	} else {
+		err = merkleProof.VerifyMembership(specs, root, path, value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s at height %s on chain %s: %v", ErrQueryVerification, key, proofHeight, c.ChainID, err)
+	}
+	return nil
+}
+
+// verifyIBCProofOf is verifyIBCProof for the proto encoding of value
+func (c *Chain) verifyIBCProofOf(key []byte, value codec.ProtoMarshaler, proof []byte,
+	proofHeight clienttypes.Height) error {
+	if !c.VerifyQueries {
+		return nil
+	}
+
+	bz, err := c.Encoding.Marshaler.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.verifyIBCProof(key, bz, proof, proofHeight)
+}
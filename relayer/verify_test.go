@@ -0,0 +1,264 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v2/modules/core/04-channel/types"
+	committypes "github.com/cosmos/ibc-go/v2/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v2/modules/core/24-host"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/log"
+	provtypes "github.com/tendermint/tendermint/light/provider"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	tmversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const testVerifyChainID = "ibc-0"
+
+// testLightProvider serves light blocks with the given app hash signed by a single validator, a
+// block is the same every time it is requested
+type testLightProvider struct {
+	t       *testing.T
+	appHash []byte
+	valSet  *tmtypes.ValidatorSet
+	privVal tmtypes.PrivValidator
+	start   time.Time
+}
+
+func newTestLightProvider(t *testing.T, appHash []byte) *testLightProvider {
+	valSet, privVals := tmtypes.RandValidatorSet(1, 10)
+	return &testLightProvider{
+		t:       t,
+		appHash: appHash,
+		valSet:  valSet,
+		privVal: privVals[0],
+		start:   time.Now().Add(-time.Hour).Truncate(time.Second),
+	}
+}
+
+func (p *testLightProvider) ChainID() string {
+	return testVerifyChainID
+}
+
+func (p *testLightProvider) LightBlock(_ context.Context, height int64) (*tmtypes.LightBlock, error) {
+	blockTime := p.start.Add(time.Duration(height) * time.Second)
+	header := &tmtypes.Header{
+		Version:            tmversion.Consensus{Block: version.BlockProtocol},
+		ChainID:            testVerifyChainID,
+		Height:             height,
+		Time:               blockTime,
+		AppHash:            p.appHash,
+		ValidatorsHash:     p.valSet.Hash(),
+		NextValidatorsHash: p.valSet.Hash(),
+		ProposerAddress:    p.valSet.Proposer.Address,
+	}
+	blockID := tmtypes.BlockID{
+		Hash:          header.Hash(),
+		PartSetHeader: tmtypes.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+	}
+	voteSet := tmtypes.NewVoteSet(testVerifyChainID, height, 1, tmproto.PrecommitType, p.valSet)
+	commit, err := tmtypes.MakeCommit(blockID, height, 1, voteSet, []tmtypes.PrivValidator{p.privVal}, blockTime)
+	require.NoError(p.t, err)
+
+	return &tmtypes.LightBlock{
+		SignedHeader: &tmtypes.SignedHeader{Header: header, Commit: commit},
+		ValidatorSet: p.valSet,
+	}, nil
+}
+
+func (p *testLightProvider) ReportEvidence(context.Context, tmtypes.Evidence) error {
+	return nil
+}
+
+// newTestIBCStore commits kvs to the IBC store of a multistore and returns it. The kvs are
+// committed at height 2, after the height the light client database trusts.
+func newTestIBCStore(t *testing.T, kvs map[string][]byte) *rootmulti.Store {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	key := storetypes.NewKVStoreKey(host.StoreKey)
+	store.MountStoreWithDB(key, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadLatestVersion())
+	store.Commit()
+
+	for k, v := range kvs {
+		store.GetKVStore(key).Set([]byte(k), v)
+	}
+	store.Commit()
+	return store
+}
+
+// newTestVerifyChain returns a chain with verify-queries set whose light blocks have the app hash
+// of store. Its light client database trusts the first light block.
+func newTestVerifyChain(t *testing.T, store *rootmulti.Store) *Chain {
+	c := &Chain{ChainID: testVerifyChainID, AccountPrefix: "cosmos", VerifyQueries: true}
+	c.Encoding = c.MakeEncodingConfig()
+
+	primary := newTestLightProvider(t, store.LastCommitID().Hash)
+	c.Provider = newLightProvider(testVerifyChainID, t.TempDir(), 24*time.Hour, primary,
+		log.NewNopLogger(), log.NewNopLogger())
+	t.Cleanup(func() { require.NoError(t, c.Close()) })
+
+	lb, err := primary.LightBlock(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = c.InitLight(1, lb.Hash())
+	require.NoError(t, err)
+	return c
+}
+
+// setPrimary makes the light client database of c verify the light blocks of primary
+func setPrimary(t *testing.T, c *Chain, primary provtypes.Provider) {
+	lp, err := c.lightProvider()
+	require.NoError(t, err)
+	require.NoError(t, lp.Close())
+	lp.primary = primary
+}
+
+// ibcProof returns the proof of key in the IBC store as it is forwarded to a counterparty chain
+func ibcProof(t *testing.T, c *Chain, store *rootmulti.Store, key []byte) ([]byte, clienttypes.Height) {
+	res := store.Query(abci.RequestQuery{
+		Path: "/" + host.StoreKey + "/key", Data: key, Height: store.LastCommitID().Version, Prove: true,
+	})
+	require.Zero(t, res.Code, res.Log)
+
+	proof, err := committypes.ConvertProofs(res.ProofOps)
+	require.NoError(t, err)
+	bz, err := c.Encoding.Marshaler.Marshal(&proof)
+	require.NoError(t, err)
+	return bz, clienttypes.NewHeight(0, uint64(res.Height))
+}
+
+func TestVerifyIBCProof(t *testing.T) {
+	var (
+		key    = host.PacketCommitmentKey("transfer", "channel-0", 1)
+		absent = host.PacketCommitmentKey("transfer", "channel-0", 2)
+		value  = []byte("commitment")
+		store  = newTestIBCStore(t, map[string][]byte{string(key): value})
+	)
+
+	c := newTestVerifyChain(t, store)
+	proof, height := ibcProof(t, c, store, key)
+	absentProof, _ := ibcProof(t, c, store, absent)
+
+	tampered := append([]byte(nil), proof...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		key     []byte
+		value   []byte
+		proof   []byte
+		appHash []byte
+		valid   bool
+	}{
+		{"value", key, value, proof, nil, true},
+		{"absence", absent, nil, absentProof, nil, true},
+		{"tampered value", key, []byte("forged"), proof, nil, false},
+		{"tampered proof", key, value, tampered, nil, false},
+		{"undecodable proof", key, value, []byte("proof"), nil, false},
+		{"other key", absent, value, proof, nil, false},
+		{"value claimed absent", key, nil, proof, nil, false},
+		{"other app hash", key, value, proof, tmhash.Sum([]byte("app")), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// light blocks of another validator set aren't verified by the trusted ones
+			c := newTestVerifyChain(t, store)
+			if tc.appHash != nil {
+				setPrimary(t, c, newTestLightProvider(t, tc.appHash))
+			}
+
+			err := c.verifyIBCProof(tc.key, tc.value, tc.proof, height)
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrQueryVerification)
+
+			// nothing is checked without verify-queries
+			c.VerifyQueries = false
+			require.NoError(t, c.verifyIBCProof(tc.key, tc.value, tc.proof, height))
+		})
+	}
+}
+
+func TestVerifyLightNotInitialized(t *testing.T) {
+	var (
+		key   = host.PacketCommitmentKey("transfer", "channel-0", 1)
+		value = []byte("commitment")
+		store = newTestIBCStore(t, map[string][]byte{string(key): value})
+		c     = newTestVerifyChain(t, store)
+	)
+	proof, height := ibcProof(t, c, store, key)
+	require.NoError(t, c.verifyIBCProof(key, value, proof, height))
+
+	// the blocks of the RPC endpoint aren't trusted without the light client database
+	require.NoError(t, c.DeleteLight())
+	require.ErrorIs(t, c.verifyIBCProof(key, value, proof, height), ErrLightNotInitialized)
+}
+
+func TestVerifyIBCProofOf(t *testing.T) {
+	var (
+		key     = host.ChannelKey("transfer", "channel-0")
+		channel = chantypes.NewChannel(chantypes.OPEN, chantypes.UNORDERED,
+			chantypes.NewCounterparty("transfer", "channel-1"), []string{"connection-0"}, "ics20-1")
+	)
+
+	c := newTestVerifyChain(t, newTestIBCStore(t, nil))
+	bz, err := c.Encoding.Marshaler.Marshal(&channel)
+	require.NoError(t, err)
+
+	store := newTestIBCStore(t, map[string][]byte{string(key): bz})
+	c = newTestVerifyChain(t, store)
+	proof, height := ibcProof(t, c, store, key)
+
+	require.NoError(t, c.verifyIBCProofOf(key, &channel, proof, height))
+
+	tampered := channel
+	tampered.State = chantypes.CLOSED
+	require.ErrorIs(t, c.verifyIBCProofOf(key, &tampered, proof, height), ErrQueryVerification)
+}
+
+func TestVerifyABCIResponse(t *testing.T) {
+	var (
+		key   = host.ConnectionKey("connection-0")
+		path  = "/store/" + host.StoreKey + "/key"
+		store = newTestIBCStore(t, map[string][]byte{string(key): []byte("connection")})
+		c     = newTestVerifyChain(t, store)
+	)
+
+	query := func() abci.ResponseQuery {
+		res := store.Query(abci.RequestQuery{
+			Path: "/" + host.StoreKey + "/key", Data: key, Height: store.LastCommitID().Version, Prove: true,
+		})
+		require.Zero(t, res.Code, res.Log)
+		return res
+	}
+
+	require.NoError(t, c.verifyABCIResponse(path, query()))
+
+	tests := []struct {
+		name   string
+		tamper func(res *abci.ResponseQuery)
+	}{
+		{"tampered value", func(res *abci.ResponseQuery) { res.Value = []byte("forged") }},
+		{"value claimed absent", func(res *abci.ResponseQuery) { res.Value = nil }},
+		{"other key", func(res *abci.ResponseQuery) { res.Key = host.ConnectionKey("connection-1") }},
+		{"no proof", func(res *abci.ResponseQuery) { res.ProofOps = nil }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := query()
+			tc.tamper(&res)
+			require.ErrorIs(t, c.verifyABCIResponse(path, res), ErrQueryVerification)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	chantypes "github.com/cosmos/ibc-go/v2/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// mockBankServer serves canned balances for any address
+type mockBankServer struct {
+	banktypes.UnimplementedQueryServer
+
+	balances sdk.Coins
+}
+
+func (s *mockBankServer) AllBalances(
+	context.Context, *banktypes.QueryAllBalancesRequest) (*banktypes.QueryAllBalancesResponse, error) {
+	return &banktypes.QueryAllBalancesResponse{Balances: s.balances}, nil
+}
+
+// mockChannelServer serves canned channel queries and records the heights they were made at
+type mockChannelServer struct {
+	chantypes.UnimplementedQueryServer
+
+	channels    []*chantypes.IdentifiedChannel
+	clientState *chantypes.QueryChannelClientStateResponse
+	unreceived  []uint64
+
+	mu      sync.Mutex
+	heights []int64
+}
+
+func (s *mockChannelServer) Channels(
+	ctx context.Context, _ *chantypes.QueryChannelsRequest) (*chantypes.QueryChannelsResponse, error) {
+	s.recordHeight(ctx)
+	return &chantypes.QueryChannelsResponse{Channels: s.channels}, nil
+}
+
+func (s *mockChannelServer) ChannelClientState(
+	ctx context.Context, _ *chantypes.QueryChannelClientStateRequest) (*chantypes.QueryChannelClientStateResponse, error) {
+	s.recordHeight(ctx)
+	return s.clientState, nil
+}
+
+func (s *mockChannelServer) UnreceivedPackets(
+	ctx context.Context, _ *chantypes.QueryUnreceivedPacketsRequest) (*chantypes.QueryUnreceivedPacketsResponse, error) {
+	s.recordHeight(ctx)
+	return &chantypes.QueryUnreceivedPacketsResponse{Sequences: s.unreceived}, nil
+}
+
+// recordHeight records the block height header of the query, 0 if it has none
+func (s *mockChannelServer) recordHeight(ctx context.Context) {
+	var height int64
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(grpctypes.GRPCBlockHeightHeader); len(vals) == 1 {
+			height, _ = strconv.ParseInt(vals[0], 10, 64)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heights = append(s.heights, height)
+}
+
+// startMockGRPCServer serves the given bank and channel query servers on a free local port
+// until the test ends and returns the address they are served on
+func startMockGRPCServer(t *testing.T, bank banktypes.QueryServer, channels chantypes.QueryServer) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	banktypes.RegisterQueryServer(srv, bank)
+	chantypes.RegisterQueryServer(srv, channels)
+
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/cosmos/relayer/relayer"
@@ -61,7 +62,8 @@ func TestAkashToGaiaStreamingRelayer(t *testing.T) {
 	require.NoError(t, dst.WaitForNBlocks(1))
 
 	// start the relayer process in it's own goroutine
-	rlyDone, err := relayer.RunStrategy(src, dst, path.MustGetStrategy())
+	ctx, cancel := context.WithCancel(context.Background())
+	rlyDone, err := relayer.RunStrategy(ctx, src, dst, path.MustGetStrategy(), 0)
 	require.NoError(t, err)
 
 	// Wait for relay message inclusion in both chains
@@ -76,7 +78,8 @@ func TestAkashToGaiaStreamingRelayer(t *testing.T) {
 	require.NoError(t, dst.WaitForNBlocks(6))
 
 	// kill relayer routine
-	rlyDone()
+	cancel()
+	require.NoError(t, rlyDone(context.Background()))
 
 	// check balance on src against expected
 	srcGot, err := src.QueryBalance(src.Key)
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/cosmos/relayer/relayer"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGaiaLinkDryRun runs the steps of rly tx link --dry-run, which must simulate the first
+// transaction of each handshake without creating anything or modifying the path
+func TestGaiaLinkDryRun(t *testing.T) {
+	chains := spinUpTestChains(t, gaiaChains...)
+
+	var (
+		src = chains.MustGet("ibc-0")
+		dst = chains.MustGet("ibc-1")
+	)
+
+	_, err := genTestPathAndSet(src, dst, "transfer", "transfer")
+	require.NoError(t, err)
+
+	src.SetDryRun(true)
+	dst.SetDryRun(true)
+
+	modified, err := src.CreateClients(dst, true, true, false)
+	require.NoError(t, err)
+	require.False(t, modified)
+	require.Empty(t, src.PathEnd.ClientID)
+	require.Empty(t, dst.PathEnd.ClientID)
+
+	modified, err = src.CreateOpenConnections(dst, 3, src.GetTimeout())
+	require.NoError(t, err)
+	require.False(t, modified)
+	require.Empty(t, src.PathEnd.ConnectionID)
+	require.Empty(t, dst.PathEnd.ConnectionID)
+
+	modified, err = src.CreateOpenChannels(dst, 3, src.GetTimeout())
+	require.NoError(t, err)
+	require.False(t, modified)
+	require.Empty(t, src.PathEnd.ChannelID)
+	require.Empty(t, dst.PathEnd.ChannelID)
+
+	// nothing was broadcast
+	for _, c := range []*relayer.Chain{src, dst} {
+		clients, err := c.QueryClients(relayer.DefaultPageRequest())
+		require.NoError(t, err)
+		for _, cs := range clients.ClientStates {
+			require.NotContains(t, cs.ClientId, "07-tendermint")
+		}
+	}
+
+	// the path links once transactions are broadcast
+	src.SetDryRun(false)
+	dst.SetDryRun(false)
+
+	_, err = src.CreateClients(dst, true, true, false)
+	require.NoError(t, err)
+	testClientPair(t, src, dst)
+
+	_, err = src.CreateOpenConnections(dst, 3, src.GetTimeout())
+	require.NoError(t, err)
+	testConnectionPair(t, src, dst)
+
+	_, err = src.CreateOpenChannels(dst, 3, src.GetTimeout())
+	require.NoError(t, err)
+	testChannelPair(t, src, dst)
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -70,7 +71,8 @@ func TestGaiaToGaiaStreamingRelayer(t *testing.T) {
 	require.NoError(t, dst.WaitForNBlocks(1))
 
 	// start the relayer process in it's own goroutine
-	rlyDone, err := relayer.RunStrategy(src, dst, path.MustGetStrategy())
+	ctx, cancel := context.WithCancel(context.Background())
+	rlyDone, err := relayer.RunStrategy(ctx, src, dst, path.MustGetStrategy(), 0)
 	require.NoError(t, err)
 
 	// Wait for relay message inclusion in both chains
@@ -85,7 +87,8 @@ func TestGaiaToGaiaStreamingRelayer(t *testing.T) {
 	require.NoError(t, dst.WaitForNBlocks(6))
 
 	// kill relayer routine
-	rlyDone()
+	cancel()
+	require.NoError(t, rlyDone(context.Background()))
 
 	// check balance on src against expected
 	srcGot, err := src.QueryBalance(src.Key)
@@ -198,7 +201,8 @@ func TestGaiaMisbehaviourMonitoring(t *testing.T) {
 	testChannelPair(t, src, dst)
 
 	// start the relayer process in it's own goroutine
-	rlyDone, err := relayer.RunStrategy(src, dst, path.MustGetStrategy())
+	ctx, cancel := context.WithCancel(context.Background())
+	rlyDone, err := relayer.RunStrategy(ctx, src, dst, path.MustGetStrategy(), 0)
 	require.NoError(t, err)
 
 	// Wait for relay message inclusion in both chains
@@ -237,7 +241,7 @@ func TestGaiaMisbehaviourMonitoring(t *testing.T) {
 	updateMsg, err := clienttypes.NewMsgUpdateClient(src.PathEnd.ClientID, newHeader, src.MustGetAddress())
 	require.NoError(t, err)
 
-	res, success, err := src.SendMsg(updateMsg)
+	res, success, err := src.SendMsg(context.Background(), updateMsg)
 	require.NoError(t, err)
 	require.True(t, success)
 	require.Equal(t, uint32(0), res.Code)
@@ -246,7 +250,8 @@ func TestGaiaMisbehaviourMonitoring(t *testing.T) {
 	require.NoError(t, dst.WaitForNBlocks(6))
 
 	// kill relayer routine
-	rlyDone()
+	cancel()
+	require.NoError(t, rlyDone(context.Background()))
 
 	clientState, err = src.QueryTMClientState(0)
 	require.NoError(t, err)
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v2/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v2/modules/core/04-channel/types"
+	ibctmtypes "github.com/cosmos/ibc-go/v2/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/relayer"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGRPCQueries tests that queries without proofs of a chain with a grpc-addr go to its gRPC
+// endpoint. The chain's RPC endpoint isn't reachable, so any query sent to it fails.
+func TestGRPCQueries(t *testing.T) {
+	clientState, err := codectypes.NewAnyWithValue(&ibctmtypes.ClientState{ChainId: "ibc-1"})
+	require.NoError(t, err)
+
+	var (
+		bank = &mockBankServer{balances: sdk.NewCoins(sdk.NewInt64Coin("stake", 1000))}
+		chns = &mockChannelServer{
+			channels: []*chantypes.IdentifiedChannel{{PortId: "transfer", ChannelId: "channel-0"}},
+			clientState: &chantypes.QueryChannelClientStateResponse{
+				IdentifiedClientState: &clienttypes.IdentifiedClientState{ClientId: "07-tendermint-0", ClientState: clientState},
+			},
+			unreceived: []uint64{2, 3},
+		}
+		addr = startMockGRPCServer(t, bank, chns)
+	)
+
+	c := &relayer.Chain{
+		Key:            "testkey",
+		ChainID:        "ibc-0",
+		RPCAddr:        "http://127.0.0.1:1",
+		GRPCAddr:       addr,
+		AccountPrefix:  "cosmos",
+		GasAdjustment:  1.3,
+		GasPrices:      "0.01stake",
+		TrustingPeriod: "330h",
+	}
+	require.NoError(t, c.Init(t.TempDir(), time.Second, nil, false))
+	require.NoError(t, c.AddPath("07-tendermint-0", "connection-0", "channel-0", "transfer", "UNORDERED"))
+
+	coins, err := c.QueryBalanceWithAddress(sdk.AccAddress(make([]byte, 20)).String())
+	require.NoError(t, err)
+	require.Equal(t, bank.balances, coins)
+
+	chans, err := c.QueryChannels(relayer.DefaultPageRequest())
+	require.NoError(t, err)
+	require.Len(t, chans.Channels, 1)
+	require.Equal(t, "channel-0", chans.Channels[0].ChannelId)
+
+	// the client state Any is unpacked like it is for ABCI queries
+	res, err := c.QueryChannelClient(10)
+	require.NoError(t, err)
+	cs, err := clienttypes.UnpackClientState(res.IdentifiedClientState.ClientState)
+	require.NoError(t, err)
+	require.Equal(t, "ibc-1", cs.(*ibctmtypes.ClientState).ChainId)
+
+	unreceived, err := c.QueryUnreceivedPackets(12, []uint64{1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2, 3}, unreceived)
+
+	// queries at a height carry it in the block height header
+	require.Equal(t, []int64{0, 10, 12}, chns.heights)
+}
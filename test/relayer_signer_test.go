@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/relayer/relayer"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGaiaSignerDaemon uses a chain without local keys that signs through a signer daemon serving
+// the keys of the test chain, and checks that the daemon refuses to sign anything but relay
+// transactions and rejects requests for chains and keys it doesn't hold
+func TestGaiaSignerDaemon(t *testing.T) {
+	chains := spinUpTestChains(t, gaiaChains...)
+
+	var (
+		src      = chains.MustGet("ibc-0")
+		testCoin = sdk.NewCoin("samoleans", sdk.NewInt(1000))
+	)
+
+	srv := httptest.NewServer(relayer.SignerDaemon(testSignerToken, src))
+	defer srv.Close()
+
+	remote := remoteSignerChain(t, src, srv.URL, testSignerToken)
+	require.False(t, remote.KeyExists(remote.Key))
+	require.Equal(t, src.MustGetAddress(), remote.MustGetAddress())
+
+	// a transfer isn't a relay transaction, so the daemon doesn't sign it
+	addr, err := remote.GetAddress()
+	require.NoError(t, err)
+	done := remote.UseSDKContext()
+	msg := banktypes.NewMsgSend(addr, addr, sdk.NewCoins(testCoin))
+	done()
+
+	_, success, err := remote.SendMsg(context.Background(), msg)
+	require.Error(t, err)
+	require.False(t, success)
+
+	// a relayer without the daemon's token can't even get the public key
+	unauthorized := remoteSignerChain(t, src, srv.URL, "guess")
+	_, err = unauthorized.GetAddress()
+	require.Error(t, err)
+
+	// keys of a chain the daemon doesn't serve
+	other, err := relayer.NewRemoteSigner(srv.URL, "ibc-9", testSignerToken, src.Encoding.Marshaler)
+	require.NoError(t, err)
+	_, err = other.PubKey(src.Key)
+	require.Error(t, err)
+	_, err = other.Sign(src.Key, []byte("sign bytes"))
+	require.Error(t, err)
+
+	// a key the daemon doesn't hold
+	signer, err := relayer.NewRemoteSigner(srv.URL, src.ChainID, testSignerToken, src.Encoding.Marshaler)
+	require.NoError(t, err)
+	_, err = signer.PubKey("unknown")
+	require.Error(t, err)
+	_, err = signer.Sign("unknown", []byte("sign bytes"))
+	require.Error(t, err)
+
+	unknown := remoteSignerChain(t, src, srv.URL, testSignerToken)
+	unknown.Key = "unknown"
+	_, err = unknown.GetAddress()
+	require.Error(t, err)
+}
+
+// testSignerToken is the token of the test signer daemons
+const testSignerToken = "secret"
+
+// remoteSignerChain returns a chain with the configuration of c and an empty local keyring
+// that signs through the signer daemon at addr, authenticating with token
+func remoteSignerChain(t *testing.T, c *relayer.Chain, addr, token string) *relayer.Chain {
+	tokenFile := path.Join(t.TempDir(), "signer-token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte(token), 0600))
+
+	rc := &relayer.Chain{
+		Key:             c.Key,
+		ChainID:         c.ChainID,
+		RPCAddr:         c.RPCAddr,
+		AccountPrefix:   c.AccountPrefix,
+		GasAdjustment:   c.GasAdjustment,
+		GasPrices:       c.GasPrices,
+		TrustingPeriod:  c.TrustingPeriod,
+		Signer:          addr,
+		SignerTokenFile: tokenFile,
+	}
+	require.NoError(t, rc.Init(t.TempDir(), c.GetTimeout(), nil, false))
+	return rc
+}